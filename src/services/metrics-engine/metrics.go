@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// HealthResponse represents the health endpoint response.
+type HealthResponse struct {
+	Status  string `json:"status"`
+	Version string `json:"version"`
+}
+
+// BuildHealthResponse creates a health response with version.
+func BuildHealthResponse(version string) HealthResponse {
+	return HealthResponse{
+		Status:  "ok",
+		Version: version,
+	}
+}
+
+// MetricLabels is the base label set stamped onto every metrics_engine_*
+// series, so any series can always be attributed to the service and
+// version that emitted it.
+func MetricLabels(service, version string) map[string]string {
+	return map[string]string{
+		"service": service,
+		"version": version,
+	}
+}
+
+var (
+	eventsTotal            *prometheus.CounterVec
+	validationDuration     prometheus.Histogram
+	dlqPublishedTotal      *prometheus.CounterVec
+	mongoInsertErrorsTotal prometheus.Counter
+)
+
+// registerMetrics creates the metrics_engine_* collectors with service and
+// version (from MetricLabels) as constant labels and registers them with
+// the default registry. Called once at startup, once ServiceVersion is
+// known.
+func registerMetrics(version string) {
+	labels := prometheus.Labels(MetricLabels("metrics-engine", version))
+
+	eventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:        "metrics_engine_events_total",
+		Help:        "Total events processed, by event type and outcome.",
+		ConstLabels: labels,
+	}, []string{"event_type", "outcome"})
+
+	validationDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:        "metrics_engine_validation_duration_seconds",
+		Help:        "Schema validation latency in seconds.",
+		ConstLabels: labels,
+		Buckets:     prometheus.DefBuckets,
+	})
+
+	dlqPublishedTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name:        "metrics_engine_dlq_published_total",
+		Help:        "Total messages published to the DLQ, by error code.",
+		ConstLabels: labels,
+	}, []string{"code"})
+
+	mongoInsertErrorsTotal = promauto.NewCounter(prometheus.CounterOpts{
+		Name:        "metrics_engine_mongo_insert_errors_total",
+		Help:        "Total errors storing events in MongoDB.",
+		ConstLabels: labels,
+	})
+}
+
+// healthzHandler reports 503 as soon as ctx is done, so a load balancer
+// stops routing traffic the instant shutdown begins draining the consumer
+// instead of waiting for the drain to finish.
+func healthzHandler(ctx context.Context) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := BuildHealthResponse(ServiceVersion)
+		w.Header().Set("Content-Type", "application/json")
+		if ctx.Err() != nil {
+			resp.Status = "shutting_down"
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}
+}
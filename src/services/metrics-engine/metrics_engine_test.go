@@ -1,14 +1,20 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"testing"
 
 	"github.com/distributed-task-observatory/metrics-engine/validator"
+	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 // ReadVersion reads the VERSION file and returns the version string.
@@ -35,28 +41,6 @@ func IsValidSemVer(version string) bool {
 	return pattern.MatchString(version)
 }
 
-// HealthResponse represents the health endpoint response.
-type HealthResponse struct {
-	Status  string `json:"status"`
-	Version string `json:"version"`
-}
-
-// BuildHealthResponse creates a health response with version.
-func BuildHealthResponse(version string) HealthResponse {
-	return HealthResponse{
-		Status:  "ok",
-		Version: version,
-	}
-}
-
-// MetricLabels includes version in metric labels.
-func MetricLabels(service, version string) map[string]string {
-	return map[string]string{
-		"service": service,
-		"version": version,
-	}
-}
-
 // TestReadVersion tests that VERSION file returns valid SemVer.
 func TestReadVersion(t *testing.T) {
 	// Create a temporary VERSION file for testing
@@ -428,3 +412,373 @@ func TestEventEnvelopeWithComplexPayload(t *testing.T) {
 		t.Error("Expected duration_ms in JSON output")
 	}
 }
+
+// TestDLQErrorFromValidationMissingField tests that a gojsonschema
+// "required" message classifies as SCHEMA_MISSING_FIELD, validation, and
+// non-retryable.
+func TestDLQErrorFromValidationMissingField(t *testing.T) {
+	got := DLQErrorFromValidation(validator.ValidationError{
+		Field:   "eventId",
+		Message: "eventId is required",
+	})
+
+	if got.Code != CodeSchemaMissingField {
+		t.Errorf("Expected code %s, got %s", CodeSchemaMissingField, got.Code)
+	}
+	if got.Category != CategoryValidation {
+		t.Errorf("Expected category %s, got %s", CategoryValidation, got.Category)
+	}
+	if got.Retryable {
+		t.Error("Expected a missing-field error not to be retryable")
+	}
+}
+
+// TestDLQErrorFromValidationTypeMismatch tests that a gojsonschema
+// "Invalid type" message classifies as SCHEMA_TYPE_MISMATCH.
+func TestDLQErrorFromValidationTypeMismatch(t *testing.T) {
+	got := DLQErrorFromValidation(validator.ValidationError{
+		Field:   "payload.duration_ms",
+		Message: "Invalid type. Expected: integer, given: string",
+	})
+
+	if got.Code != CodeSchemaTypeMismatch {
+		t.Errorf("Expected code %s, got %s", CodeSchemaTypeMismatch, got.Code)
+	}
+	if got.Retryable {
+		t.Error("Expected a type-mismatch error not to be retryable")
+	}
+}
+
+// TestDLQErrorFromValidationUnparseablePayload tests that a parse-failure
+// message classifies as PAYLOAD_UNPARSEABLE, transport, and retryable —
+// redelivery might succeed once the transient issue clears.
+func TestDLQErrorFromValidationUnparseablePayload(t *testing.T) {
+	got := DLQErrorFromValidation(validator.ValidationError{
+		Field:   "$",
+		Message: "failed to parse message: unexpected end of JSON input",
+	})
+
+	if got.Code != CodePayloadUnparseable {
+		t.Errorf("Expected code %s, got %s", CodePayloadUnparseable, got.Code)
+	}
+	if got.Category != CategoryTransport {
+		t.Errorf("Expected category %s, got %s", CategoryTransport, got.Category)
+	}
+	if !got.Retryable {
+		t.Error("Expected a parse failure to be retryable")
+	}
+}
+
+// TestDLQErrorFromValidationUnsupportedContractVersion tests that an error
+// on the contractVersion field classifies as CONTRACT_VERSION_UNSUPPORTED.
+func TestDLQErrorFromValidationUnsupportedContractVersion(t *testing.T) {
+	got := DLQErrorFromValidation(validator.ValidationError{
+		Field:   "contractVersion",
+		Message: "unsupported contract version 9.9.9: not found",
+	})
+
+	if got.Code != CodeContractVersionUnsupported {
+		t.Errorf("Expected code %s, got %s", CodeContractVersionUnsupported, got.Code)
+	}
+	if got.Category != CategoryContract {
+		t.Errorf("Expected category %s, got %s", CategoryContract, got.Category)
+	}
+	if got.Retryable {
+		t.Error("Expected an unsupported contract version not to be retryable")
+	}
+}
+
+// TestDlqErrorsFromValidationPreservesOrderAndCount tests that every input
+// error produces one classified DLQError, in order.
+func TestDlqErrorsFromValidationPreservesOrderAndCount(t *testing.T) {
+	errs := []validator.ValidationError{
+		{Field: "eventId", Message: "eventId is required"},
+		{Field: "$", Message: "failed to parse message: bad json"},
+	}
+
+	got := dlqErrorsFromValidation(errs)
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 classified errors, got %d", len(got))
+	}
+	if got[0].Code != CodeSchemaMissingField {
+		t.Errorf("Expected first error to be %s, got %s", CodeSchemaMissingField, got[0].Code)
+	}
+	if got[1].Code != CodePayloadUnparseable {
+		t.Errorf("Expected second error to be %s, got %s", CodePayloadUnparseable, got[1].Code)
+	}
+}
+
+// TestDLQMessageCarriesStructuredErrorsAlongsideDeprecatedString tests that
+// DLQMessage serializes both Errors and the deprecated ValidationError
+// string together.
+func TestDLQMessageCarriesStructuredErrorsAlongsideDeprecatedString(t *testing.T) {
+	dlqMsg := DLQMessage{
+		OriginalEvent: json.RawMessage(`{"eventType":"job.failed"}`),
+		Errors: []DLQError{
+			{Code: CodeSchemaMissingField, Category: CategoryValidation, HTTPStatus: 400, Field: "eventId", Message: "eventId is required"},
+		},
+		Summary:         "eventId: eventId is required",
+		ValidationError: "eventId: eventId is required",
+		RejectedAt:      "2024-01-01T00:00:00Z",
+		CorrelationID:   "corr-789",
+		Service:         "metrics-engine",
+	}
+
+	data, err := json.Marshal(dlqMsg)
+	if err != nil {
+		t.Fatalf("Failed to marshal DLQMessage: %v", err)
+	}
+
+	var decoded DLQMessage
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal DLQMessage: %v", err)
+	}
+
+	if len(decoded.Errors) != 1 || decoded.Errors[0].Code != CodeSchemaMissingField {
+		t.Errorf("Expected one structured error with code %s, got %v", CodeSchemaMissingField, decoded.Errors)
+	}
+	if decoded.ValidationError == "" {
+		t.Error("Expected the deprecated ValidationError string to still be populated")
+	}
+}
+
+// TestFanoutModeEnabled tests that fanout mode is only enabled by the exact
+// REPLICATION_MODE=fanout value.
+func TestFanoutModeEnabled(t *testing.T) {
+	os.Unsetenv("REPLICATION_MODE")
+	if fanoutModeEnabled() {
+		t.Error("Expected fanout mode to be disabled by default")
+	}
+
+	os.Setenv("REPLICATION_MODE", "fanout")
+	defer os.Unsetenv("REPLICATION_MODE")
+	if !fanoutModeEnabled() {
+		t.Error("Expected REPLICATION_MODE=fanout to enable fanout mode")
+	}
+}
+
+// TestIngestForcedByEnv tests that REPLICATION_INGEST is parsed
+// case-insensitively and defaults to false.
+func TestIngestForcedByEnv(t *testing.T) {
+	os.Unsetenv("REPLICATION_INGEST")
+	if ingestForcedByEnv() {
+		t.Error("Expected ingest to not be forced by default")
+	}
+
+	os.Setenv("REPLICATION_INGEST", "TRUE")
+	defer os.Unsetenv("REPLICATION_INGEST")
+	if !ingestForcedByEnv() {
+		t.Error("Expected REPLICATION_INGEST=TRUE to force ingest")
+	}
+}
+
+// TestReplicaQueueName tests that the replica queue name is derived from
+// the given identity.
+func TestReplicaQueueName(t *testing.T) {
+	got := replicaQueueName("host-1.42")
+	want := "jobs.completed.replica.host-1.42"
+	if got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+// TestReplicaIdentityIncludesPID tests that replicaIdentity embeds the
+// current process ID, so two replicas on the same host get distinct names.
+func TestReplicaIdentityIncludesPID(t *testing.T) {
+	got := replicaIdentity()
+	if !strings.Contains(got, strconv.Itoa(os.Getpid())) {
+		t.Errorf("Expected identity %q to contain the current PID", got)
+	}
+}
+
+// TestReplicatedHeadersPreservesOriginalAndStampsSource tests that
+// replicatedHeaders copies existing headers and adds ReplicatedFromHeader
+// without mutating the input.
+func TestReplicatedHeadersPreservesOriginalAndStampsSource(t *testing.T) {
+	original := amqp.Table{"x-custom": "value"}
+
+	got := replicatedHeaders(original, "host-1.42")
+
+	if got["x-custom"] != "value" {
+		t.Errorf("Expected original header to be preserved, got %v", got)
+	}
+	if got[ReplicatedFromHeader] != "host-1.42" {
+		t.Errorf("Expected %s to be set to the replica identity, got %v", ReplicatedFromHeader, got[ReplicatedFromHeader])
+	}
+	if _, ok := original[ReplicatedFromHeader]; ok {
+		t.Error("Expected the original headers table not to be mutated")
+	}
+}
+
+// TestRetryDelayMsDoublesPerAttempt tests the exponential backoff
+// computation for a few attempts, uncapped.
+func TestRetryDelayMsDoublesPerAttempt(t *testing.T) {
+	cases := []struct {
+		attempt int
+		want    int
+	}{
+		{0, 500},
+		{1, 1000},
+		{2, 2000},
+	}
+	for _, tc := range cases {
+		if got := retryDelayMs(500, 30000, tc.attempt); got != tc.want {
+			t.Errorf("retryDelayMs(500, 30000, %d) = %d, want %d", tc.attempt, got, tc.want)
+		}
+	}
+}
+
+// TestRetryDelayMsCapsAtMax tests that the backoff never exceeds maxMs.
+func TestRetryDelayMsCapsAtMax(t *testing.T) {
+	if got := retryDelayMs(500, 5000, 10); got != 5000 {
+		t.Errorf("Expected the backoff to be capped at 5000, got %d", got)
+	}
+}
+
+// TestRetryTimelineDecodesXDeath tests that retryTimeline decodes an
+// x-death header into RetryAttempt entries.
+func TestRetryTimelineDecodesXDeath(t *testing.T) {
+	headers := amqp.Table{
+		"x-death": []interface{}{
+			amqp.Table{"queue": RetryQueueName, "reason": "expired", "count": int64(2)},
+		},
+	}
+
+	got := retryTimeline(headers)
+
+	if len(got) != 1 {
+		t.Fatalf("Expected 1 retry attempt, got %d", len(got))
+	}
+	if got[0].Queue != RetryQueueName || got[0].Reason != "expired" || got[0].Attempt != 2 {
+		t.Errorf("Expected {%s expired 2}, got %+v", RetryQueueName, got[0])
+	}
+}
+
+// TestRetryTimelineNoHeader tests that a message with no x-death header
+// decodes to an empty timeline.
+func TestRetryTimelineNoHeader(t *testing.T) {
+	if got := retryTimeline(amqp.Table{}); got != nil {
+		t.Errorf("Expected a nil timeline, got %v", got)
+	}
+}
+
+// TestDeathCountReadsRetryQueueExpiredEntry tests that deathCount extracts
+// the attempt count for RetryQueueName's "expired" reason specifically,
+// ignoring unrelated x-death entries.
+func TestDeathCountReadsRetryQueueExpiredEntry(t *testing.T) {
+	headers := amqp.Table{
+		"x-death": []interface{}{
+			amqp.Table{"queue": "some-other-queue", "reason": "rejected", "count": int64(9)},
+			amqp.Table{"queue": RetryQueueName, "reason": "expired", "count": int64(3)},
+		},
+	}
+
+	if got := deathCount(headers); got != 3 {
+		t.Errorf("Expected deathCount 3, got %d", got)
+	}
+}
+
+// TestHealthzHandlerOKBeforeShutdown tests that healthzHandler returns 200
+// while its context is still live.
+func TestHealthzHandlerOKBeforeShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	w := httptest.NewRecorder()
+	healthzHandler(ctx)(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 before shutdown, got %d", w.Code)
+	}
+
+	var resp HealthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Status != "ok" {
+		t.Errorf("Expected status 'ok', got %q", resp.Status)
+	}
+}
+
+// TestHealthzHandlerUnavailableAfterShutdown tests that healthzHandler
+// returns 503 as soon as its context is canceled.
+func TestHealthzHandlerUnavailableAfterShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	w := httptest.NewRecorder()
+	healthzHandler(ctx)(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected 503 after shutdown, got %d", w.Code)
+	}
+}
+
+// fakeAcknowledger records which of Ack/Nack/Reject a handler calls, so
+// tests can assert on delivery disposition without a live AMQP connection.
+type fakeAcknowledger struct {
+	acked   bool
+	nacked  bool
+	requeue bool
+}
+
+func (f *fakeAcknowledger) Ack(tag uint64, multiple bool) error { f.acked = true; return nil }
+func (f *fakeAcknowledger) Nack(tag uint64, multiple, requeue bool) error {
+	f.nacked = true
+	f.requeue = requeue
+	return nil
+}
+func (f *fakeAcknowledger) Reject(tag uint64, requeue bool) error { return nil }
+
+// fakePublisher implements amqpPublisher, failing PublishWithContext for
+// whichever routing key matches failKey and recording every key it saw.
+type fakePublisher struct {
+	failKey string
+	keys    []string
+}
+
+func (f *fakePublisher) PublishWithContext(ctx context.Context, exchange, key string, mandatory, immediate bool, msg amqp.Publishing) error {
+	f.keys = append(f.keys, key)
+	if key == f.failKey {
+		return fmt.Errorf("publish to %s failed", key)
+	}
+	return nil
+}
+
+// TestHandleValidationFailureFallsThroughToDLQOnRetryPublishFailure tests
+// that a retryable validation failure whose publish to RetryQueueName
+// fails isn't silently Acked and dropped: it must fall through to the DLQ
+// publish and Nack, not Ack, the original delivery.
+func TestHandleValidationFailureFallsThroughToDLQOnRetryPublishFailure(t *testing.T) {
+	pub := &fakePublisher{failKey: RetryQueueName}
+	ack := &fakeAcknowledger{}
+	msg := amqp.Delivery{Acknowledger: ack}
+	result := validator.ValidationResult{
+		Valid:  false,
+		Errors: []validator.ValidationError{{Field: "payload", Message: "failed to parse payload"}},
+	}
+
+	// The DLQ path increments dlqPublishedTotal, which main registers at
+	// startup; register it here once so the counter isn't nil.
+	if dlqPublishedTotal == nil {
+		registerMetrics("0.0.0-test")
+	}
+
+	handleValidationFailure(context.Background(), pub, msg, result, "corr-1", retryPolicy{MaxRetries: 3, BaseMs: 100, MaxMs: 1000})
+
+	if ack.acked {
+		t.Error("Expected message not to be Acked when the retry-queue publish failed")
+	}
+	if !ack.nacked {
+		t.Fatal("Expected message to be Nacked after falling through to the DLQ")
+	}
+	if ack.requeue {
+		t.Error("Expected the DLQ fallback Nack not to requeue")
+	}
+	if len(pub.keys) != 2 || pub.keys[0] != RetryQueueName || pub.keys[1] != DLQName {
+		t.Errorf("Expected a retry-queue publish attempt followed by a DLQ publish attempt, got %v", pub.keys)
+	}
+}
@@ -0,0 +1,168 @@
+package validator
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// schemaRegistry fetches versioned schema documents from a remote HTTP
+// registry keyed by schema name and contract version.
+type schemaRegistry struct {
+	baseURL string
+	client  *http.Client
+}
+
+func newSchemaRegistry(baseURL string, client *http.Client) *schemaRegistry {
+	return &schemaRegistry{baseURL: baseURL, client: client}
+}
+
+// fetchSchema retrieves the raw schema document for (name, version); the
+// caller compiles it.
+func (r *schemaRegistry) fetchSchema(name, version string) ([]byte, error) {
+	url := fmt.Sprintf("%s/schemas/%s/%s.json", r.baseURL, name, version)
+	resp, err := r.client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schema %s@%s: %w", name, version, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned %d for schema %s@%s", resp.StatusCode, name, version)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// schemaIndexEntry is one row of the registry's /schemas/index.json.
+type schemaIndexEntry struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// fetchIndex retrieves the registry's current list of (name, version) pairs.
+func (r *schemaRegistry) fetchIndex(ctx context.Context) ([]schemaIndexEntry, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.baseURL+"/schemas/index.json", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch schema index: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry returned %d for schema index", resp.StatusCode)
+	}
+
+	var entries []schemaIndexEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode schema index: %w", err)
+	}
+	return entries, nil
+}
+
+// versionedSchemaCache is a bounded LRU cache of compiled schemas keyed by
+// (schemaName, contractVersion), so that ValidateMessageForVersion doesn't
+// re-fetch and recompile a schema on every call.
+type versionedSchemaCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+type versionedCacheEntry struct {
+	key    string
+	schema *gojsonschema.Schema
+}
+
+func newVersionedSchemaCache(capacity int) *versionedSchemaCache {
+	return &versionedSchemaCache{
+		capacity: capacity,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+	}
+}
+
+func versionedCacheKey(schemaName, contractVersion string) string {
+	return schemaName + "@" + contractVersion
+}
+
+func (c *versionedSchemaCache) get(key string) (*gojsonschema.Schema, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*versionedCacheEntry).schema, true
+}
+
+func (c *versionedSchemaCache) put(key string, schema *gojsonschema.Schema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		el.Value.(*versionedCacheEntry).schema = schema
+		return
+	}
+
+	el := c.ll.PushFront(&versionedCacheEntry{key: key, schema: schema})
+	c.items[key] = el
+
+	for c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*versionedCacheEntry).key)
+	}
+}
+
+// WatchRegistry polls the registry's schema index every interval and
+// recompiles any schema listed there, swapping the cached copy in place so
+// concurrent ValidateMessageForVersion calls pick up the refreshed schema on
+// their next cache read. It blocks until ctx is cancelled, or returns at
+// once if no registry is configured.
+func (v *Validator) WatchRegistry(ctx context.Context, interval time.Duration) {
+	if v.registry == nil {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			v.refreshFromIndex(ctx)
+		}
+	}
+}
+
+func (v *Validator) refreshFromIndex(ctx context.Context) {
+	entries, err := v.registry.fetchIndex(ctx)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if _, err := v.refreshSchema(entry.Name, entry.Version); err != nil {
+			continue
+		}
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
+	"io/fs"
+	"net/http"
+	"path"
 	"sync"
 
 	"github.com/xeipuuv/gojsonschema"
 )
 
-//go:embed schemas/*.json
+//go:embed schemas
 var schemasFS embed.FS
 
 // ValidationError represents a schema validation failure
@@ -28,13 +31,39 @@ type ValidationResult struct {
 type Validator struct {
 	schemas map[string]*gojsonschema.Schema
 	mu      sync.RWMutex
+
+	registry   *schemaRegistry
+	versioned  *versionedSchemaCache
+	refLoaders []gojsonschema.JSONLoader
+}
+
+// Option configures optional Validator behavior at construction time.
+type Option func(*Validator)
+
+// WithRemoteRegistry points the validator at an HTTP schema registry used by
+// ValidateMessageForVersion to resolve schemas for contract versions that
+// aren't compiled in locally.
+func WithRemoteRegistry(baseURL string) Option {
+	return func(v *Validator) {
+		v.registry = newSchemaRegistry(baseURL, http.DefaultClient)
+	}
 }
 
 // NewValidator creates a new schema validator
-func NewValidator() (*Validator, error) {
+func NewValidator(opts ...Option) (*Validator, error) {
 	v := &Validator{
-		schemas: make(map[string]*gojsonschema.Schema),
+		schemas:   make(map[string]*gojsonschema.Schema),
+		versioned: newVersionedSchemaCache(32),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	refLoaders, err := loadReferenceSchemas()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reference schemas: %w", err)
 	}
+	v.refLoaders = refLoaders
 
 	// Pre-load schemas
 	schemaNames := []string{"event-envelope", "job"}
@@ -47,14 +76,43 @@ func NewValidator() (*Validator, error) {
 	return v, nil
 }
 
+// loadReferenceSchemas walks schemas/ (including subdirectories, e.g.
+// schemas/common/) and returns a loader for every file found so that
+// top-level schemas can $ref one another by $id regardless of nesting.
+func loadReferenceSchemas() ([]gojsonschema.JSONLoader, error) {
+	var loaders []gojsonschema.JSONLoader
+	err := fs.WalkDir(schemasFS, "schemas", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || path.Ext(p) != ".json" {
+			return nil
+		}
+		data, err := schemasFS.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		loaders = append(loaders, gojsonschema.NewBytesLoader(data))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loaders, nil
+}
+
 func (v *Validator) loadSchema(name string) error {
 	data, err := schemasFS.ReadFile(fmt.Sprintf("schemas/%s.json", name))
 	if err != nil {
 		return fmt.Errorf("failed to read schema file: %w", err)
 	}
 
-	loader := gojsonschema.NewBytesLoader(data)
-	schema, err := gojsonschema.NewSchema(loader)
+	sl := gojsonschema.NewSchemaLoader()
+	if err := sl.AddSchemas(v.refLoaders...); err != nil {
+		return fmt.Errorf("failed to register reference schemas: %w", err)
+	}
+
+	schema, err := sl.Compile(gojsonschema.NewBytesLoader(data))
 	if err != nil {
 		return fmt.Errorf("failed to compile schema: %w", err)
 	}
@@ -125,6 +183,84 @@ func (v *Validator) ValidateMessage(message []byte) ValidationResult {
 	return ValidationResult{Valid: true}
 }
 
+// ValidateMessageForVersion validates message against the event-envelope
+// schema published for its contractVersion. Messages with no contractVersion,
+// and every message when no registry is configured via WithRemoteRegistry,
+// fall back to ValidateMessage. Otherwise every contractVersion, including
+// one the locally-compiled schemas already cover, is resolved through the
+// registry and cached for subsequent calls; the locally-compiled schemas
+// are not consulted, and only the envelope (not the payload) is validated.
+func (v *Validator) ValidateMessageForVersion(message []byte) ValidationResult {
+	var envelope struct {
+		ContractVersion string `json:"contractVersion"`
+	}
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return ValidationResult{
+			Valid: false,
+			Errors: []ValidationError{{
+				Field:   "$",
+				Message: fmt.Sprintf("failed to parse message: %v", err),
+			}},
+		}
+	}
+
+	if envelope.ContractVersion == "" || v.registry == nil {
+		return v.ValidateMessage(message)
+	}
+
+	schema, err := v.schemaForVersion("event-envelope", envelope.ContractVersion)
+	if err != nil {
+		return ValidationResult{
+			Valid: false,
+			Errors: []ValidationError{{
+				Field:   "contractVersion",
+				Message: fmt.Sprintf("unsupported contract version %s: %v", envelope.ContractVersion, err),
+			}},
+		}
+	}
+
+	result, err := runSchema(schema, message)
+	if err != nil {
+		return ValidationResult{
+			Valid: false,
+			Errors: []ValidationError{{Field: "$", Message: err.Error()}},
+		}
+	}
+	return result
+}
+
+// schemaForVersion returns the compiled schema for (name, contractVersion),
+// fetching and compiling it from the registry on a cache miss.
+func (v *Validator) schemaForVersion(name, contractVersion string) (*gojsonschema.Schema, error) {
+	key := versionedCacheKey(name, contractVersion)
+	if schema, ok := v.versioned.get(key); ok {
+		return schema, nil
+	}
+	return v.refreshSchema(name, contractVersion)
+}
+
+// refreshSchema fetches and compiles (name, contractVersion) from the
+// registry unconditionally, replacing any cached copy. Used both for cache
+// misses and by WatchRegistry's periodic hot-reload.
+func (v *Validator) refreshSchema(name, contractVersion string) (*gojsonschema.Schema, error) {
+	data, err := v.registry.fetchSchema(name, contractVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	sl := gojsonschema.NewSchemaLoader()
+	if err := sl.AddSchemas(v.refLoaders...); err != nil {
+		return nil, fmt.Errorf("failed to register reference schemas: %w", err)
+	}
+	schema, err := sl.Compile(gojsonschema.NewBytesLoader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile schema %s@%s: %w", name, contractVersion, err)
+	}
+
+	v.versioned.put(versionedCacheKey(name, contractVersion), schema)
+	return schema, nil
+}
+
 func (v *Validator) validate(schemaName string, data []byte) ValidationResult {
 	v.mu.RLock()
 	schema, ok := v.schemas[schemaName]
@@ -140,8 +276,7 @@ func (v *Validator) validate(schemaName string, data []byte) ValidationResult {
 		}
 	}
 
-	documentLoader := gojsonschema.NewBytesLoader(data)
-	result, err := schema.Validate(documentLoader)
+	result, err := runSchema(schema, data)
 	if err != nil {
 		return ValidationResult{
 			Valid: false,
@@ -151,6 +286,17 @@ func (v *Validator) validate(schemaName string, data []byte) ValidationResult {
 			}},
 		}
 	}
+	return result
+}
+
+// runSchema validates data against an already-compiled schema and converts
+// gojsonschema's result errors into the package's ValidationError shape.
+func runSchema(schema *gojsonschema.Schema, data []byte) (ValidationResult, error) {
+	documentLoader := gojsonschema.NewBytesLoader(data)
+	result, err := schema.Validate(documentLoader)
+	if err != nil {
+		return ValidationResult{}, err
+	}
 
 	if !result.Valid() {
 		errors := make([]ValidationError, 0, len(result.Errors()))
@@ -160,10 +306,10 @@ func (v *Validator) validate(schemaName string, data []byte) ValidationResult {
 				Message: err.Description(),
 			})
 		}
-		return ValidationResult{Valid: false, Errors: errors}
+		return ValidationResult{Valid: false, Errors: errors}, nil
 	}
 
-	return ValidationResult{Valid: true}
+	return ValidationResult{Valid: true}, nil
 }
 
 // GetCorrelationID extracts the correlation ID from an event for logging
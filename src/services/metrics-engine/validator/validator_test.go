@@ -1,6 +1,9 @@
 package validator
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
@@ -189,3 +192,116 @@ func TestValidationResultStruct(t *testing.T) {
 		t.Errorf("Expected field 'eventId', got '%s'", result.Errors[0].Field)
 	}
 }
+
+// registryFixture is a minimal in-memory stand-in for a remote schema
+// registry, serving schemas/index.json and per-version schema documents.
+func registryFixture(t *testing.T) *httptest.Server {
+	const v2Schema = `{
+		"$id": "https://schemas.oddessentials.com/event-envelope/2.0.0.json",
+		"type": "object",
+		"required": ["contractVersion", "eventType", "eventId"],
+		"properties": {
+			"contractVersion": {"type": "string"},
+			"eventType": {"type": "string"},
+			"eventId": {"type": "string"}
+		}
+	}`
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/schemas/index.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[{"name":"event-envelope","version":"2.0.0"}]`))
+	})
+	mux.HandleFunc("/schemas/event-envelope/2.0.0.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(v2Schema))
+	})
+	mux.HandleFunc("/schemas/event-envelope/9.9.9.json", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestValidateMessageForVersionFetchesFromRegistry tests that a message
+// whose contractVersion has no local schema is validated against the schema
+// fetched from the registry.
+func TestValidateMessageForVersionFetchesFromRegistry(t *testing.T) {
+	server := registryFixture(t)
+
+	v, err := NewValidator(WithRemoteRegistry(server.URL))
+	if err != nil {
+		t.Fatalf("NewValidator failed: %v", err)
+	}
+
+	message := []byte(`{"contractVersion":"2.0.0","eventType":"job.created","eventId":"evt-1"}`)
+	result := v.ValidateMessageForVersion(message)
+	if !result.Valid {
+		t.Errorf("Expected valid, got invalid with errors: %+v", result.Errors)
+	}
+}
+
+// TestValidateMessageForVersionCachesCompiledSchema tests that the first
+// lookup for a (name, version) leaves the compiled schema in the versioned
+// cache, and that a second lookup for the same pair is still valid.
+func TestValidateMessageForVersionCachesCompiledSchema(t *testing.T) {
+	server := registryFixture(t)
+
+	v, err := NewValidator(WithRemoteRegistry(server.URL))
+	if err != nil {
+		t.Fatalf("NewValidator failed: %v", err)
+	}
+
+	message := []byte(`{"contractVersion":"2.0.0","eventType":"job.created","eventId":"evt-1"}`)
+	first := v.ValidateMessageForVersion(message)
+	if !first.Valid {
+		t.Fatalf("Expected first call valid, got errors: %+v", first.Errors)
+	}
+
+	if _, ok := v.versioned.get(versionedCacheKey("event-envelope", "2.0.0")); !ok {
+		t.Error("Expected compiled schema to be cached after first lookup")
+	}
+
+	second := v.ValidateMessageForVersion(message)
+	if !second.Valid {
+		t.Errorf("Expected cached lookup valid, got errors: %+v", second.Errors)
+	}
+}
+
+// TestValidateMessageForVersionUnknownVersion tests that a contractVersion
+// the registry doesn't recognize surfaces as a validation error rather than
+// a panic.
+func TestValidateMessageForVersionUnknownVersion(t *testing.T) {
+	server := registryFixture(t)
+
+	v, err := NewValidator(WithRemoteRegistry(server.URL))
+	if err != nil {
+		t.Fatalf("NewValidator failed: %v", err)
+	}
+
+	message := []byte(`{"contractVersion":"9.9.9","eventType":"job.created","eventId":"evt-1"}`)
+	result := v.ValidateMessageForVersion(message)
+	if result.Valid {
+		t.Error("Expected invalid result for unknown contract version")
+	}
+}
+
+// TestWatchRegistryRefreshesCache tests that refreshFromIndex, the step
+// WatchRegistry runs on every tick, populates the cache for every schema
+// listed in the registry's index. The ticker loop itself is not exercised.
+func TestWatchRegistryRefreshesCache(t *testing.T) {
+	server := registryFixture(t)
+
+	v, err := NewValidator(WithRemoteRegistry(server.URL))
+	if err != nil {
+		t.Fatalf("NewValidator failed: %v", err)
+	}
+
+	v.refreshFromIndex(context.Background())
+
+	if _, ok := v.versioned.get(versionedCacheKey("event-envelope", "2.0.0")); !ok {
+		t.Error("Expected refreshFromIndex to populate the cache from the registry index")
+	}
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/distributed-task-observatory/read-model/apierrors"
+)
+
+// adminPaths holds operator-registered OpenAPI path-item fragments, merged
+// into the spec served by openApiHandler on the next request.
+var adminPaths = newPathFragmentRegistry()
+
+// adminJobTypes holds operator-registered Job.Type values, consulted by
+// recentJobsHandler when a caller filters by type. seedJobTypesFromDB
+// populates it with every type already present in the jobs table at
+// startup, so registering one new type doesn't retroactively turn
+// filtering on every pre-existing type into a 400.
+var adminJobTypes = newJobTypeRegistry()
+
+// seedJobTypesFromDB registers every distinct Job.Type already present in
+// the jobs table, so an operator registering one new type via
+// PUT /admin/jobs/types/{type} doesn't turn adminJobTypes.allowed into a
+// restrictive allowlist for types that predate the registry.
+func seedJobTypesFromDB(ctx context.Context, db *sql.DB) error {
+	rows, err := db.QueryContext(ctx, "SELECT DISTINCT type FROM jobs")
+	if err != nil {
+		return fmt.Errorf("querying distinct job types: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var typ string
+		if err := rows.Scan(&typ); err != nil {
+			return fmt.Errorf("scanning job type: %w", err)
+		}
+		adminJobTypes.upsert(typ)
+	}
+	return rows.Err()
+}
+
+// pathFragmentRegistry is a concurrency-safe map of OpenAPI path to the
+// path-item fragment an operator registered for it.
+type pathFragmentRegistry struct {
+	mu        sync.RWMutex
+	fragments map[string]map[string]interface{}
+}
+
+func newPathFragmentRegistry() *pathFragmentRegistry {
+	return &pathFragmentRegistry{fragments: make(map[string]map[string]interface{})}
+}
+
+// upsert stores fragment under path, reporting whether it replaced an
+// already-registered fragment rather than inserting a new one.
+func (r *pathFragmentRegistry) upsert(path string, fragment map[string]interface{}) (replaced bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, replaced = r.fragments[path]
+	r.fragments[path] = fragment
+	return replaced
+}
+
+// snapshot returns a copy of the registered fragments, keyed by path.
+func (r *pathFragmentRegistry) snapshot() map[string]map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]map[string]interface{}, len(r.fragments))
+	for k, v := range r.fragments {
+		out[k] = v
+	}
+	return out
+}
+
+// jobTypeRegistry is a concurrency-safe set of operator-registered
+// Job.Type values.
+type jobTypeRegistry struct {
+	mu    sync.RWMutex
+	types map[string]bool
+}
+
+func newJobTypeRegistry() *jobTypeRegistry {
+	return &jobTypeRegistry{types: make(map[string]bool)}
+}
+
+// upsert registers typ, reporting whether it was already registered.
+func (r *jobTypeRegistry) upsert(typ string) (replaced bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	replaced = r.types[typ]
+	r.types[typ] = true
+	return replaced
+}
+
+// allowed reports whether typ may be used as a query filter: true if it's
+// registered, or if no operator has registered anything yet (an empty
+// registry doesn't restrict a service that shipped without one). Once
+// seedJobTypesFromDB has found at least one type at startup, a type is
+// rejected unless it was in the jobs table then or has been registered
+// since.
+func (r *jobTypeRegistry) allowed(typ string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.types) == 0 {
+		return true
+	}
+	return r.types[typ]
+}
+
+// requireAdminToken guards an admin handler behind the ADMIN_TOKEN env var,
+// checked as a Bearer Authorization header. An unset ADMIN_TOKEN disables
+// every admin endpoint rather than treating a missing token as "no auth
+// required".
+func requireAdminToken(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		token := getEnv("ADMIN_TOKEN", "")
+		got := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			httpError(w, r, fmt.Errorf("admin token missing or invalid: %w", apierrors.ErrForbidden))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// adminUpsertOpenAPIPathHandler registers or replaces the path-item
+// fragment openApiHandler merges into the spec under path.
+func adminUpsertOpenAPIPathHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/admin/openapi/paths/")
+	if path == "" {
+		httpError(w, r, fmt.Errorf("path is required: %w", apierrors.ErrInvalid))
+		return
+	}
+
+	var fragment map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&fragment); err != nil {
+		httpError(w, r, fmt.Errorf("fragment is not valid JSON: %w", apierrors.ErrInvalid))
+		return
+	}
+
+	if adminPaths.upsert("/"+path, fragment) {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// adminListOpenAPIPathsHandler lists every operator-registered path
+// fragment, for introspection.
+func adminListOpenAPIPathsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(adminPaths.snapshot())
+}
+
+// adminUpsertJobTypeHandler registers typ as an allowed Job.Type value.
+func adminUpsertJobTypeHandler(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	typ := strings.TrimPrefix(r.URL.Path, "/admin/jobs/types/")
+	if typ == "" {
+		httpError(w, r, fmt.Errorf("type is required: %w", apierrors.ErrInvalid))
+		return
+	}
+
+	if adminJobTypes.upsert(typ) {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusCreated)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// TestPathFragmentRegistryUpsert tests that upsert reports replacement
+// correctly and snapshot reflects what was stored.
+func TestPathFragmentRegistryUpsert(t *testing.T) {
+	reg := newPathFragmentRegistry()
+
+	if replaced := reg.upsert("/widgets", map[string]interface{}{"get": struct{}{}}); replaced {
+		t.Error("Expected first upsert to report no replacement")
+	}
+	if replaced := reg.upsert("/widgets", map[string]interface{}{"put": struct{}{}}); !replaced {
+		t.Error("Expected second upsert of the same path to report a replacement")
+	}
+
+	snap := reg.snapshot()
+	if _, ok := snap["/widgets"]["put"]; !ok {
+		t.Errorf("Expected snapshot to contain the latest fragment, got %v", snap)
+	}
+}
+
+// TestJobTypeRegistryAllowed tests that an empty registry allows anything,
+// but a populated one restricts to what's registered.
+func TestJobTypeRegistryAllowed(t *testing.T) {
+	reg := newJobTypeRegistry()
+
+	if !reg.allowed("anything") {
+		t.Error("Expected an empty registry to allow any type")
+	}
+
+	reg.upsert("backup")
+	if !reg.allowed("backup") {
+		t.Error("Expected a registered type to be allowed")
+	}
+	if reg.allowed("unregistered") {
+		t.Error("Expected an unregistered type to be disallowed once the registry is non-empty")
+	}
+}
+
+// TestRequireAdminTokenRejectsMissingOrWrongToken tests that the middleware
+// returns 403 unless ADMIN_TOKEN is set and the Bearer token matches.
+func TestRequireAdminTokenRejectsMissingOrWrongToken(t *testing.T) {
+	os.Unsetenv("ADMIN_TOKEN")
+	called := false
+	handler := requireAdminToken(func(w http.ResponseWriter, r *http.Request) { called = true })
+
+	req := httptest.NewRequest("PUT", "/admin/jobs/types/backup", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 with no ADMIN_TOKEN set, got %d", w.Code)
+	}
+	if called {
+		t.Error("Expected the wrapped handler not to run")
+	}
+
+	os.Setenv("ADMIN_TOKEN", "secret")
+	defer os.Unsetenv("ADMIN_TOKEN")
+
+	req = httptest.NewRequest("PUT", "/admin/jobs/types/backup", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	w = httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected 403 with a wrong token, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("PUT", "/admin/jobs/types/backup", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	w = httptest.NewRecorder()
+	handler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected the wrapped handler's status with a matching token, got %d", w.Code)
+	}
+	if !called {
+		t.Error("Expected the wrapped handler to run")
+	}
+}
+
+// TestAdminUpsertJobTypeHandlerStatusCodes tests that the handler returns
+// 201 for a new type and 200 for re-registering the same one.
+func TestAdminUpsertJobTypeHandlerStatusCodes(t *testing.T) {
+	adminJobTypes = newJobTypeRegistry()
+
+	req := httptest.NewRequest("PUT", "/admin/jobs/types/backup", nil)
+	w := httptest.NewRecorder()
+	adminUpsertJobTypeHandler(w, req)
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected 201 for a new type, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("PUT", "/admin/jobs/types/backup", nil)
+	w = httptest.NewRecorder()
+	adminUpsertJobTypeHandler(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200 for re-registering an existing type, got %d", w.Code)
+	}
+}
+
+// TestAdminUpsertOpenAPIPathHandlerValidatesBody tests that invalid JSON is
+// rejected and a valid fragment is stored under the registry.
+func TestAdminUpsertOpenAPIPathHandlerValidatesBody(t *testing.T) {
+	adminPaths = newPathFragmentRegistry()
+
+	req := httptest.NewRequest("PUT", "/admin/openapi/paths/widgets", bytes.NewBufferString("not json"))
+	w := httptest.NewRecorder()
+	adminUpsertOpenAPIPathHandler(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for invalid JSON, got %d", w.Code)
+	}
+
+	req = httptest.NewRequest("PUT", "/admin/openapi/paths/widgets", bytes.NewBufferString(`{"get":{}}`))
+	w = httptest.NewRecorder()
+	adminUpsertOpenAPIPathHandler(w, req)
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected 201 for a new path, got %d", w.Code)
+	}
+	if _, ok := adminPaths.snapshot()["/widgets"]; !ok {
+		t.Error("Expected the fragment to be registered under /widgets")
+	}
+}
@@ -0,0 +1,56 @@
+// Package apierrors classifies handler errors into an HTTP status and a
+// stable error code, the way Docker's API server maps errdefs-style errors
+// to status codes instead of hardcoding one at each call site.
+package apierrors
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors a handler can wrap with fmt.Errorf("...: %w", ...) to get
+// a specific status/code from Classify without relying on message matching.
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrForbidden = errors.New("forbidden")
+	ErrConflict  = errors.New("conflict")
+	ErrInvalid   = errors.New("invalid")
+	ErrTimeout   = errors.New("timeout")
+)
+
+// Classify maps err to the HTTP status and error code a handler should
+// respond with. It checks the sentinel errors first via errors.Is, then
+// falls back to matching well-known substrings in err.Error() for errors
+// that were never wrapped (e.g. from a driver or third-party package).
+// Anything unrecognized is a 500 with code INTERNAL.
+func Classify(err error) (status int, code string) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound, "NOT_FOUND"
+	case errors.Is(err, ErrForbidden):
+		return http.StatusForbidden, "FORBIDDEN"
+	case errors.Is(err, ErrConflict):
+		return http.StatusConflict, "CONFLICT"
+	case errors.Is(err, ErrInvalid):
+		return http.StatusBadRequest, "INVALID"
+	case errors.Is(err, ErrTimeout):
+		return http.StatusGatewayTimeout, "TIMEOUT"
+	}
+
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "not found"):
+		return http.StatusNotFound, "NOT_FOUND"
+	case strings.Contains(msg, "forbidden"):
+		return http.StatusForbidden, "FORBIDDEN"
+	case strings.Contains(msg, "conflict"):
+		return http.StatusConflict, "CONFLICT"
+	case strings.Contains(msg, "invalid"):
+		return http.StatusBadRequest, "INVALID"
+	case strings.Contains(msg, "timeout"):
+		return http.StatusGatewayTimeout, "TIMEOUT"
+	default:
+		return http.StatusInternalServerError, "INTERNAL"
+	}
+}
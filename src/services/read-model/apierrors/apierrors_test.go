@@ -0,0 +1,61 @@
+package apierrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// TestClassifySentinels tests that each wrapped sentinel maps to its status
+// and code.
+func TestClassifySentinels(t *testing.T) {
+	cases := []struct {
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{fmt.Errorf("job %s: %w", "123", ErrNotFound), http.StatusNotFound, "NOT_FOUND"},
+		{fmt.Errorf("user: %w", ErrForbidden), http.StatusForbidden, "FORBIDDEN"},
+		{fmt.Errorf("job already running: %w", ErrConflict), http.StatusConflict, "CONFLICT"},
+		{fmt.Errorf("limit: %w", ErrInvalid), http.StatusBadRequest, "INVALID"},
+		{fmt.Errorf("upstream: %w", ErrTimeout), http.StatusGatewayTimeout, "TIMEOUT"},
+	}
+	for _, tc := range cases {
+		status, code := Classify(tc.err)
+		if status != tc.wantStatus || code != tc.wantCode {
+			t.Errorf("Classify(%v) = (%d, %s), want (%d, %s)", tc.err, status, code, tc.wantStatus, tc.wantCode)
+		}
+	}
+}
+
+// TestClassifyMessageFallback tests that unwrapped errors are still
+// classified by matching a well-known substring in their message.
+func TestClassifyMessageFallback(t *testing.T) {
+	cases := []struct {
+		msg        string
+		wantStatus int
+		wantCode   string
+	}{
+		{"job not found", http.StatusNotFound, "NOT_FOUND"},
+		{"operation Forbidden for this user", http.StatusForbidden, "FORBIDDEN"},
+		{"version conflict", http.StatusConflict, "CONFLICT"},
+		{"invalid limit parameter", http.StatusBadRequest, "INVALID"},
+		{"upstream request timeout", http.StatusGatewayTimeout, "TIMEOUT"},
+	}
+	for _, tc := range cases {
+		status, code := Classify(errors.New(tc.msg))
+		if status != tc.wantStatus || code != tc.wantCode {
+			t.Errorf("Classify(%q) = (%d, %s), want (%d, %s)", tc.msg, status, code, tc.wantStatus, tc.wantCode)
+		}
+	}
+}
+
+// TestClassifyDefaultsToInternal tests that an unrecognized error maps to
+// 500 INTERNAL.
+func TestClassifyDefaultsToInternal(t *testing.T) {
+	status, code := Classify(errors.New("connection reset by peer"))
+	if status != http.StatusInternalServerError || code != "INTERNAL" {
+		t.Errorf("Classify(unrecognized) = (%d, %s), want (500, INTERNAL)", status, code)
+	}
+}
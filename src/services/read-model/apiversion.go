@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// APIVersion is a (major, minor) API version, compared numerically rather
+// than lexically so v2.0 sorts after v10.0 never comes up by surprise.
+// Mirrors how Docker's API server gates behavior behind an APIVERSION
+// constant, but the Read Model API keeps several versions live at once
+// instead of a single current one.
+type APIVersion struct {
+	Major int
+	Minor int
+}
+
+var (
+	// APIVersionV1 is the original, unprefixed route behavior.
+	APIVersionV1 = APIVersion{Major: 1}
+	// APIVersionV2 is the first versioned route behavior, reached via the
+	// /v2/ URL prefix or an Accept: application/vnd.oddessentials.v2+json
+	// media type.
+	APIVersionV2 = APIVersion{Major: 2}
+
+	// CurrentAPIVersion is the latest version new clients should target.
+	CurrentAPIVersion = APIVersionV2
+
+	// DefaultAPIVersion is what a request gets when it names no version at
+	// all, preserving existing clients' behavior.
+	DefaultAPIVersion = APIVersionV1
+)
+
+// ParseAPIVersion parses "v1", "v1.2", "1", or "1.2" into an APIVersion.
+func ParseAPIVersion(raw string) (APIVersion, error) {
+	raw = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(raw)), "v")
+	major, minor, ok := strings.Cut(raw, ".")
+
+	majorN, err := strconv.Atoi(major)
+	if err != nil {
+		return APIVersion{}, fmt.Errorf("invalid API version %q", raw)
+	}
+	minorN := 0
+	if ok {
+		if minorN, err = strconv.Atoi(minor); err != nil {
+			return APIVersion{}, fmt.Errorf("invalid API version %q", raw)
+		}
+	}
+	return APIVersion{Major: majorN, Minor: minorN}, nil
+}
+
+// String renders v as e.g. "v1" or "v1.2".
+func (v APIVersion) String() string {
+	if v.Minor == 0 {
+		return fmt.Sprintf("v%d", v.Major)
+	}
+	return fmt.Sprintf("v%d.%d", v.Major, v.Minor)
+}
+
+// LessThan reports whether v precedes other.
+func (v APIVersion) LessThan(other APIVersion) bool {
+	if v.Major != other.Major {
+		return v.Major < other.Major
+	}
+	return v.Minor < other.Minor
+}
+
+// GreaterThanOrEqualTo reports whether v is at least other.
+func (v APIVersion) GreaterThanOrEqualTo(other APIVersion) bool {
+	return !v.LessThan(other)
+}
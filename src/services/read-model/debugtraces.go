@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// spanRecord is the JSON-friendly projection of a completed span served by
+// debugTracesHandler; sdktrace.ReadOnlySpan itself isn't serializable.
+type spanRecord struct {
+	TraceID    string            `json:"traceId"`
+	SpanID     string            `json:"spanId"`
+	Name       string            `json:"name"`
+	StatusCode string            `json:"statusCode"`
+	StartTime  time.Time         `json:"startTime"`
+	EndTime    time.Time         `json:"endTime"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// recentSpanBuffer is a SpanProcessor that retains the last `capacity` ended
+// spans in memory, for /debug/traces. It does not export anywhere itself;
+// it's installed alongside the OTLP batcher, not instead of it.
+type recentSpanBuffer struct {
+	mu       sync.Mutex
+	capacity int
+	spans    []spanRecord
+}
+
+func newRecentSpanBuffer(capacity int) *recentSpanBuffer {
+	return &recentSpanBuffer{capacity: capacity}
+}
+
+func (b *recentSpanBuffer) OnStart(context.Context, sdktrace.ReadWriteSpan) {}
+
+func (b *recentSpanBuffer) OnEnd(s sdktrace.ReadOnlySpan) {
+	attrs := make(map[string]string, len(s.Attributes()))
+	for _, kv := range s.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	record := spanRecord{
+		TraceID:    s.SpanContext().TraceID().String(),
+		SpanID:     s.SpanContext().SpanID().String(),
+		Name:       s.Name(),
+		StatusCode: s.Status().Code.String(),
+		StartTime:  s.StartTime(),
+		EndTime:    s.EndTime(),
+		Attributes: attrs,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.spans = append(b.spans, record)
+	if len(b.spans) > b.capacity {
+		b.spans = b.spans[len(b.spans)-b.capacity:]
+	}
+}
+
+func (b *recentSpanBuffer) Shutdown(context.Context) error   { return nil }
+func (b *recentSpanBuffer) ForceFlush(context.Context) error { return nil }
+
+// recent returns a copy of the currently buffered spans, most recent last.
+func (b *recentSpanBuffer) recent() []spanRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := make([]spanRecord, len(b.spans))
+	copy(out, b.spans)
+	return out
+}
+
+// debugTracesHandler serves the most recently completed spans as JSON, for
+// local inspection without standing up a collector.
+func debugTracesHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(recentSpans.recent())
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestDebugTracesHandlerReturnsRecordedSpans tests that a span ended via
+// tracingMiddleware shows up in the /debug/traces JSON body.
+func TestDebugTracesHandlerReturnsRecordedSpans(t *testing.T) {
+	ServiceVersion = "1.2.3"
+	prev := recentSpans
+	recentSpans = newRecentSpanBuffer(50)
+	t.Cleanup(func() { recentSpans = prev })
+
+	tr := newTracingRecorder(t)
+	req := httptest.NewRequest("GET", "/health", nil)
+	tracingMiddleware("/health", healthHandler)(tr, req)
+	recentSpans.OnEnd(tr.Ended()[0])
+
+	w := httptest.NewRecorder()
+	debugTracesHandler(w, httptest.NewRequest("GET", "/debug/traces", nil))
+
+	var spans []spanRecord
+	if err := json.NewDecoder(w.Body).Decode(&spans); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(spans) != 1 {
+		t.Fatalf("Expected exactly one recorded span, got %d", len(spans))
+	}
+	if spans[0].Name != "/health" {
+		t.Errorf("Expected span name /health, got %q", spans[0].Name)
+	}
+	if spans[0].Attributes["service.version"] != "1.2.3" {
+		t.Errorf("Expected service.version 1.2.3, got %q", spans[0].Attributes["service.version"])
+	}
+}
+
+// TestRecentSpanBufferCapsAtCapacity tests that the buffer discards the
+// oldest entries once it's full rather than growing unbounded.
+func TestRecentSpanBufferCapsAtCapacity(t *testing.T) {
+	buf := newRecentSpanBuffer(2)
+	tr := newTracingRecorder(t)
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest("GET", "/health", nil)
+		tracingMiddleware("/health", healthHandler)(httptest.NewRecorder(), req)
+	}
+	for _, s := range tr.Ended() {
+		buf.OnEnd(s)
+	}
+
+	if got := len(buf.recent()); got != 2 {
+		t.Errorf("Expected buffer capped at 2, got %d", got)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/distributed-task-observatory/read-model/apierrors"
+)
+
+type requestIDKeyType struct{}
+
+var requestIDKey requestIDKeyType
+
+// newRequestID returns a random 16-byte hex string, unique enough to let a
+// client correlate a bug report with this server's logs.
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// requestIDMiddleware generates a request ID, sets it as X-Request-ID on the
+// response, and attaches it to the request context so httpError can include
+// it in error envelopes.
+func requestIDMiddleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := newRequestID()
+		w.Header().Set("X-Request-ID", id)
+		next(w, r.WithContext(context.WithValue(r.Context(), requestIDKey, id)))
+	}
+}
+
+// requestIDFromContext returns the ID requestIDMiddleware attached to ctx,
+// or "unknown" if none is present (e.g. a handler called directly in a
+// test, without going through the middleware chain).
+func requestIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey).(string); ok {
+		return id
+	}
+	return "unknown"
+}
+
+// errorEnvelope is the JSON body httpError writes, documented as
+// components.schemas.Error in openapi.yaml.
+type errorEnvelope struct {
+	Error errorBody `json:"error"`
+}
+
+type errorBody struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"requestId"`
+}
+
+// httpError classifies err via apierrors.Classify and writes the matching
+// HTTP status with a {"error":{"code","message","requestId"}} JSON body.
+// Every handler should funnel its error paths through this instead of
+// calling w.WriteHeader or http.Error directly.
+func httpError(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := apierrors.Classify(err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorEnvelope{Error: errorBody{
+		Code:      code,
+		Message:   err.Error(),
+		RequestID: requestIDFromContext(r.Context()),
+	}})
+}
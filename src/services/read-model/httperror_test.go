@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/distributed-task-observatory/read-model/apierrors"
+)
+
+// TestHttpErrorWritesClassifiedStatus tests that httpError maps a wrapped
+// sentinel to its HTTP status and error code.
+func TestHttpErrorWritesClassifiedStatus(t *testing.T) {
+	req := httptest.NewRequest("GET", "/jobs/recent", nil)
+	w := httptest.NewRecorder()
+
+	httpError(w, req, fmt.Errorf("job 123: %w", apierrors.ErrNotFound))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+
+	var body errorEnvelope
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatalf("Failed to decode body: %v", err)
+	}
+	if body.Error.Code != "NOT_FOUND" {
+		t.Errorf("Expected code NOT_FOUND, got %q", body.Error.Code)
+	}
+	if body.Error.Message == "" {
+		t.Error("Expected a non-empty message")
+	}
+}
+
+// TestHttpErrorDefaultsToInternal tests that an unclassified error becomes
+// a 500 with code INTERNAL.
+func TestHttpErrorDefaultsToInternal(t *testing.T) {
+	req := httptest.NewRequest("GET", "/stats", nil)
+	w := httptest.NewRecorder()
+
+	httpError(w, req, errors.New("connection reset by peer"))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected 500, got %d", w.Code)
+	}
+
+	var body errorEnvelope
+	json.NewDecoder(w.Body).Decode(&body)
+	if body.Error.Code != "INTERNAL" {
+		t.Errorf("Expected code INTERNAL, got %q", body.Error.Code)
+	}
+}
+
+// TestHttpErrorIncludesRequestID tests that the request ID attached by
+// requestIDMiddleware ends up in the error body.
+func TestHttpErrorIncludesRequestID(t *testing.T) {
+	var gotID string
+	handler := requestIDMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		httpError(w, r, errors.New("invalid limit"))
+		gotID = requestIDFromContext(r.Context())
+	})
+
+	req := httptest.NewRequest("GET", "/jobs/recent", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if headerID := w.Header().Get("X-Request-ID"); headerID == "" || headerID != gotID {
+		t.Errorf("Expected X-Request-ID header to match context value, got header %q vs context %q", headerID, gotID)
+	}
+
+	var body errorEnvelope
+	json.NewDecoder(w.Body).Decode(&body)
+	if body.Error.RequestID != gotID {
+		t.Errorf("Expected body requestId %q, got %q", gotID, body.Error.RequestID)
+	}
+}
+
+// TestRequestIDFromContextFallback tests that a request without the
+// middleware still gets a stable placeholder rather than an empty string.
+func TestRequestIDFromContextFallback(t *testing.T) {
+	req := httptest.NewRequest("GET", "/health", nil)
+	if got := requestIDFromContext(req.Context()); got != "unknown" {
+		t.Errorf("Expected 'unknown', got %q", got)
+	}
+}
@@ -0,0 +1,86 @@
+// Package logging builds the Read Model API's structured logger and the
+// middleware that threads a per-request, correlation-ID-tagged logger
+// through context.Context.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// ParseLevel validates LOG_LEVEL, accepting Debug/Info/Warn/Error
+// case-insensitively.
+func ParseLevel(raw string) (slog.Level, error) {
+	switch strings.ToLower(raw) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("must be one of Debug, Info, Warn, Error, got %q", raw)
+	}
+}
+
+// New builds the service's base logger, writing JSON lines to stdout at the
+// given level.
+func New(level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: level}))
+}
+
+type loggerKey struct{}
+
+// FromContext returns the logger a Middleware call attached to ctx, or
+// fallback if ctx carries none (e.g. outside a request, or in a test that
+// built its own context).
+func FromContext(ctx context.Context, fallback *slog.Logger) *slog.Logger {
+	if l, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return fallback
+}
+
+// newCorrelationID returns a random 16-byte hex string for requests that
+// didn't supply their own X-Correlation-Id.
+func newCorrelationID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Middleware reads the request's X-Correlation-Id, generating one if absent,
+// and echoes it on both the request (so downstream handlers and the OpenAPI
+// validator see it too) and the response. It attaches a logger carrying
+// {route, method, correlationId, remote} to the request context, retrievable
+// via FromContext.
+func Middleware(base *slog.Logger, route string) func(http.HandlerFunc) http.HandlerFunc {
+	return func(next http.HandlerFunc) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			correlationID := r.Header.Get("X-Correlation-Id")
+			if correlationID == "" {
+				correlationID = newCorrelationID()
+				r.Header.Set("X-Correlation-Id", correlationID)
+			}
+			w.Header().Set("X-Correlation-Id", correlationID)
+
+			reqLogger := base.With(
+				"route", route,
+				"method", r.Method,
+				"correlationId", correlationID,
+				"remote", r.RemoteAddr,
+			)
+			next(w, r.WithContext(context.WithValue(r.Context(), loggerKey{}, reqLogger)))
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// recordingHandler is a minimal slog.Handler that captures every record's
+// attributes so tests can assert on them without parsing JSON output.
+// records is a pointer shared across WithAttrs copies so the original
+// handler's caller can inspect everything logged through any derived logger.
+type recordingHandler struct {
+	records *[]map[string]string
+	with    []slog.Attr
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := map[string]string{}
+	for _, a := range h.with {
+		attrs[a.Key] = a.Value.String()
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.String()
+		return true
+	})
+	if h.records == nil {
+		h.records = &[]map[string]string{}
+	}
+	*h.records = append(*h.records, attrs)
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if h.records == nil {
+		h.records = &[]map[string]string{}
+	}
+	return &recordingHandler{records: h.records, with: append(append([]slog.Attr{}, h.with...), attrs...)}
+}
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+// TestParseLevelAccepted tests that each documented LOG_LEVEL value parses.
+func TestParseLevelAccepted(t *testing.T) {
+	cases := map[string]slog.Level{
+		"Debug": slog.LevelDebug,
+		"info":  slog.LevelInfo,
+		"WARN":  slog.LevelWarn,
+		"Error": slog.LevelError,
+	}
+	for raw, want := range cases {
+		got, err := ParseLevel(raw)
+		if err != nil {
+			t.Errorf("ParseLevel(%q) returned error: %v", raw, err)
+		}
+		if got != want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", raw, got, want)
+		}
+	}
+}
+
+// TestParseLevelRejectsUnknown tests that an unrecognized level is an error.
+func TestParseLevelRejectsUnknown(t *testing.T) {
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("Expected error for unknown level, got none")
+	}
+}
+
+// TestMiddlewarePropagatesSuppliedCorrelationID tests that a correlation ID
+// on the request is echoed on the response and attached to the logger.
+func TestMiddlewarePropagatesSuppliedCorrelationID(t *testing.T) {
+	h := &recordingHandler{}
+	base := slog.New(h)
+
+	var loggedID string
+	handler := Middleware(base, "/jobs/recent")(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context(), base).Info("handled")
+		loggedID = (*h.records)[len(*h.records)-1]["correlationId"]
+	})
+
+	req := httptest.NewRequest("GET", "/jobs/recent", nil)
+	req.Header.Set("X-Correlation-Id", "req-123")
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if got := w.Header().Get("X-Correlation-Id"); got != "req-123" {
+		t.Errorf("Expected response correlation ID 'req-123', got %q", got)
+	}
+	if loggedID != "req-123" {
+		t.Errorf("Expected logged correlationId 'req-123', got %q", loggedID)
+	}
+}
+
+// TestMiddlewareGeneratesMissingCorrelationID tests that a request without a
+// correlation ID gets one generated and echoed consistently.
+func TestMiddlewareGeneratesMissingCorrelationID(t *testing.T) {
+	h := &recordingHandler{}
+	base := slog.New(h)
+
+	var loggedID string
+	handler := Middleware(base, "/stats")(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context(), base).Info("handled")
+		loggedID = (*h.records)[len(*h.records)-1]["correlationId"]
+	})
+
+	req := httptest.NewRequest("GET", "/stats", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	respID := w.Header().Get("X-Correlation-Id")
+	if respID == "" {
+		t.Fatal("Expected a generated correlation ID on the response, got none")
+	}
+	if loggedID != respID {
+		t.Errorf("Expected logged correlationId to match response header %q, got %q", respID, loggedID)
+	}
+}
+
+// TestMiddlewareAttachesRouteAndMethod tests that the request-scoped logger
+// carries the route and method the middleware was configured with.
+func TestMiddlewareAttachesRouteAndMethod(t *testing.T) {
+	h := &recordingHandler{}
+	base := slog.New(h)
+
+	handler := Middleware(base, "/events")(func(w http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context(), base).Info("handled")
+	})
+
+	req := httptest.NewRequest("POST", "/events", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	got := (*h.records)[len(*h.records)-1]
+	if got["route"] != "/events" {
+		t.Errorf("Expected route '/events', got %q", got["route"])
+	}
+	if got["method"] != "POST" {
+		t.Errorf("Expected method 'POST', got %q", got["method"])
+	}
+}
+
+// TestFromContextFallback tests that FromContext returns the fallback
+// logger when ctx carries none.
+func TestFromContextFallback(t *testing.T) {
+	fallback := slog.New(&recordingHandler{})
+	if got := FromContext(context.Background(), fallback); got != fallback {
+		t.Error("Expected FromContext to return the fallback logger")
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	httpRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_requests_total",
+		Help: "Total HTTP requests by route, method, and status.",
+	}, []string{"route", "method", "status"})
+
+	httpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "HTTP request latency in seconds by route and method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method"})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Backend query latency in seconds by backend and operation.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"backend", "op"})
+)
+
+// observeDBQuery records how long a backend call took under
+// db_query_duration_seconds{backend,op}.
+func observeDBQuery(backend, op string, start time.Time) {
+	dbQueryDuration.WithLabelValues(backend, op).Observe(time.Since(start).Seconds())
+}
+
+// statusRecorder is a minimal ResponseWriter shim that captures the status
+// code a handler wrote so instrumentMiddleware can label it after the fact.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.wroteHeader = true
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.WriteHeader(http.StatusOK)
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush passes through to the wrapped ResponseWriter's Flusher, if it has
+// one, so middleware wrapping statusRecorder doesn't hide streaming
+// capability (e.g. SSE handlers) from downstream http.Flusher assertions.
+func (r *statusRecorder) Flush() {
+	if f, ok := r.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// instrumentMiddleware records http_requests_total and
+// http_request_duration_seconds for every request to route, wrapping
+// corsMiddleware so CORS preflight responses are captured too.
+func instrumentMiddleware(route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next(rec, r)
+
+		httpRequestsTotal.WithLabelValues(route, r.Method, strconv.Itoa(rec.status)).Inc()
+		httpRequestDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// scrapeMetrics fires promhttp.Handler and returns the raw exposition text,
+// so tests can assert on specific counter/histogram lines without wiring up
+// a full Prometheus client.
+func scrapeMetrics(t *testing.T) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	promhttp.Handler().ServeHTTP(rec, req)
+	return rec.Body.String()
+}
+
+func TestInstrumentMiddlewareRecordsRequestsTotal(t *testing.T) {
+	handler := instrumentMiddleware("/test/requests-total", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test/requests-total", nil)
+	handler(httptest.NewRecorder(), req)
+
+	body := scrapeMetrics(t)
+	if !strings.Contains(body, `http_requests_total{method="GET",route="/test/requests-total",status="200"}`) {
+		t.Errorf("expected http_requests_total series for route, got body:\n%s", body)
+	}
+}
+
+func TestInstrumentMiddlewareRecordsStatusFromHandler(t *testing.T) {
+	handler := instrumentMiddleware("/test/status-from-handler", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test/status-from-handler", nil)
+	handler(httptest.NewRecorder(), req)
+
+	body := scrapeMetrics(t)
+	if !strings.Contains(body, `http_requests_total{method="GET",route="/test/status-from-handler",status="500"}`) {
+		t.Errorf("expected http_requests_total series with status 500, got body:\n%s", body)
+	}
+}
+
+func TestInstrumentMiddlewareRecordsDuration(t *testing.T) {
+	handler := instrumentMiddleware("/test/duration", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/test/duration", nil)
+	handler(httptest.NewRecorder(), req)
+
+	body := scrapeMetrics(t)
+	if !strings.Contains(body, `http_request_duration_seconds_count{method="GET",route="/test/duration"}`) {
+		t.Errorf("expected http_request_duration_seconds_count series, got body:\n%s", body)
+	}
+}
+
+func TestObserveDBQueryRecordsBackendDuration(t *testing.T) {
+	observeDBQuery("redis", "get_test", time.Now())
+
+	body := scrapeMetrics(t)
+	if !strings.Contains(body, `db_query_duration_seconds_count{backend="redis",op="get_test"}`) {
+		t.Errorf("expected db_query_duration_seconds_count series, got body:\n%s", body)
+	}
+}
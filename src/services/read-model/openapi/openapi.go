@@ -0,0 +1,217 @@
+// Package openapi parses the Read Model API's OpenAPI spec and provides
+// middleware that validates requests and responses against it at runtime.
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	legacyrouter "github.com/getkin/kin-openapi/routers/legacy"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	validatorValidationsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "validator_validations_total",
+		Help: "Total OpenAPI response validations by route and result.",
+	}, []string{"schema", "result"})
+
+	validatorErrorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "validator_errors_total",
+		Help: "Total OpenAPI response validation errors by route and field.",
+	}, []string{"schema", "field"})
+)
+
+//go:embed openapi.yaml
+var specFS embed.FS
+
+// Mode controls what happens when a response fails validation.
+type Mode int
+
+const (
+	// ModeReport logs response validation failures but still serves the response.
+	ModeReport Mode = iota
+	// ModeStrict returns a 500 when a response fails validation.
+	ModeStrict
+)
+
+// ValidationError describes a single request/response validation failure.
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is an aggregated set of ValidationError, returned for
+// every failure found rather than just the first.
+type ValidationErrors struct {
+	Errors []ValidationError `json:"errors"`
+}
+
+// Validator parses the embedded OpenAPI spec and validates HTTP traffic
+// against it.
+type Validator struct {
+	doc    *openapi3.T
+	router routers.Router
+	mode   Mode
+}
+
+// NewValidator loads openapi.yaml and builds a router used to match
+// incoming requests to their documented operation.
+func NewValidator(mode Mode) (*Validator, error) {
+	data, err := specFS.ReadFile("openapi.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded spec: %w", err)
+	}
+
+	doc, err := openapi3.NewLoader().LoadFromData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, fmt.Errorf("spec failed validation: %w", err)
+	}
+
+	router, err := legacyrouter.NewRouter(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build router: %w", err)
+	}
+
+	return &Validator{doc: doc, router: router, mode: mode}, nil
+}
+
+// Doc returns the parsed spec so handlers (e.g. the /openapi.json endpoint)
+// can re-serve it.
+func (v *Validator) Doc() *openapi3.T {
+	return v.doc
+}
+
+// Middleware validates path/query parameters on the way in and the JSON
+// response body on the way out against the matching OpenAPI operation. In
+// ModeStrict an invalid response is replaced with a 500; in ModeReport it is
+// only logged.
+func (v *Validator) Middleware(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			// Not every registered handler needs to be documented (yet);
+			// fall through undocumented rather than blocking the request.
+			next(w, r)
+			return
+		}
+
+		reqInput := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    &openapi3filter.Options{MultiError: true, ExcludeRequestBody: true},
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), reqInput); err != nil {
+			writeValidationErrors(w, http.StatusBadRequest, flattenErrors(err))
+			return
+		}
+
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK, body: &bytes.Buffer{}}
+		next(rec, r)
+
+		respInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: reqInput,
+			Status:                 rec.status,
+			Header:                 rec.Header(),
+			Options:                &openapi3filter.Options{MultiError: true},
+		}
+		respInput.SetBodyBytes(rec.body.Bytes())
+
+		if err := openapi3filter.ValidateResponse(r.Context(), respInput); err != nil {
+			validatorValidationsTotal.WithLabelValues(r.URL.Path, "invalid").Inc()
+			for _, fieldErr := range flattenErrors(err).Errors {
+				validatorErrorsTotal.WithLabelValues(r.URL.Path, fieldErr.Field).Inc()
+			}
+
+			correlationID := CorrelationID(r)
+			if v.mode == ModeStrict {
+				http.Error(w, "response failed contract validation", http.StatusInternalServerError)
+				return
+			}
+			log.Printf("[%s] openapi: response validation failed for %s %s: %v", correlationID, r.Method, r.URL.Path, err)
+		} else {
+			validatorValidationsTotal.WithLabelValues(r.URL.Path, "valid").Inc()
+		}
+
+		rec.flush()
+	}
+}
+
+// responseRecorder buffers a handler's response so it can be validated
+// before being written to the real ResponseWriter.
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	body        *bytes.Buffer
+	wroteHeader bool
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.wroteHeader = true
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	if !r.wroteHeader {
+		r.wroteHeader = true
+	}
+	return r.body.Write(b)
+}
+
+func (r *responseRecorder) flush() {
+	r.ResponseWriter.WriteHeader(r.status)
+	r.ResponseWriter.Write(r.body.Bytes())
+}
+
+// flattenErrors converts a kin-openapi MultiError (or a single error) into
+// the repo's aggregated ValidationErrors shape.
+func flattenErrors(err error) ValidationErrors {
+	var me openapi3.MultiError
+	if asMultiError(err, &me) {
+		out := ValidationErrors{}
+		for _, e := range me {
+			out.Errors = append(out.Errors, ValidationError{Field: "", Message: e.Error()})
+		}
+		return out
+	}
+	return ValidationErrors{Errors: []ValidationError{{Message: err.Error()}}}
+}
+
+func asMultiError(err error, target *openapi3.MultiError) bool {
+	me, ok := err.(openapi3.MultiError)
+	if !ok {
+		return false
+	}
+	*target = me
+	return true
+}
+
+// CorrelationID returns the request's X-Correlation-Id header, defaulting to
+// "unknown" so log lines and error responses always have a stable value to
+// key on even when the caller didn't set one.
+func CorrelationID(r *http.Request) string {
+	id := r.Header.Get("X-Correlation-Id")
+	if id == "" {
+		return "unknown"
+	}
+	return id
+}
+
+func writeValidationErrors(w http.ResponseWriter, status int, errs ValidationErrors) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errs)
+}
@@ -0,0 +1,171 @@
+package openapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// scrapeMetrics fires promhttp.Handler and returns the raw exposition text.
+func scrapeMetrics(t *testing.T) string {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	promhttp.Handler().ServeHTTP(rec, req)
+	return rec.Body.String()
+}
+
+func newTestValidator(t *testing.T, mode Mode) *Validator {
+	v, err := NewValidator(mode)
+	if err != nil {
+		t.Fatalf("NewValidator failed: %v", err)
+	}
+	return v
+}
+
+// TestMiddlewarePassesValidResponse tests that a response matching the
+// documented schema is forwarded unchanged.
+func TestMiddlewarePassesValidResponse(t *testing.T) {
+	v := newTestValidator(t, ModeReport)
+
+	handler := v.Middleware(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"totalJobs":1,"completedJobs":1,"failedJobs":0,"lastEventTime":"2024-01-01T00:00:00Z"}`))
+	})
+
+	req := httptest.NewRequest("GET", "/stats", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
+
+// TestMiddlewareReportModeLogsButServesMangledResponse tests that ModeReport
+// serves an invalid response rather than rejecting it.
+func TestMiddlewareReportModeLogsButServesMangledResponse(t *testing.T) {
+	v := newTestValidator(t, ModeReport)
+
+	handler := v.Middleware(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Missing the required totalJobs/completedJobs/failedJobs fields.
+		w.Write([]byte(`{"lastEventTime":"2024-01-01T00:00:00Z"}`))
+	})
+
+	req := httptest.NewRequest("GET", "/stats", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected ModeReport to still serve the response with 200, got %d", w.Code)
+	}
+}
+
+// TestMiddlewareStrictModeRejectsMangledResponse tests that ModeStrict turns
+// a schema-invalid response into a 500.
+func TestMiddlewareStrictModeRejectsMangledResponse(t *testing.T) {
+	v := newTestValidator(t, ModeStrict)
+
+	handler := v.Middleware(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"lastEventTime":"2024-01-01T00:00:00Z"}`))
+	})
+
+	req := httptest.NewRequest("GET", "/stats", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected ModeStrict to reject an invalid response with 500, got %d", w.Code)
+	}
+}
+
+// TestMiddlewareValidatesJobsRecentResponse tests /jobs/recent response
+// validation against the JobsPage schema's Job items.
+func TestMiddlewareValidatesJobsRecentResponse(t *testing.T) {
+	v := newTestValidator(t, ModeStrict)
+
+	handler := v.Middleware(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// status/createdAt are required on each Job but missing here.
+		w.Write([]byte(`{"items":[{"id":"job-1","type":"compute"}]}`))
+	})
+
+	req := httptest.NewRequest("GET", "/jobs/recent", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected invalid Job array to be rejected with 500, got %d", w.Code)
+	}
+}
+
+// TestMiddlewareValidatesEventsQueryParam tests that a request with the
+// documented jobId query parameter and an EventsPage response validates
+// cleanly against /events; in ModeStrict either failing would not be a 200.
+func TestMiddlewareValidatesEventsQueryParam(t *testing.T) {
+	v := newTestValidator(t, ModeStrict)
+
+	handler := v.Middleware(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"items":[{"eventId":"evt-1"}]}`))
+	})
+
+	req := httptest.NewRequest("GET", "/events?jobId=job-1", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
+
+// TestMiddlewareRecordsValidationMetrics tests that valid and invalid
+// responses are counted under validator_validations_total and that an
+// invalid response also increments validator_errors_total per field.
+func TestMiddlewareRecordsValidationMetrics(t *testing.T) {
+	v := newTestValidator(t, ModeReport)
+
+	handler := v.Middleware(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"lastEventTime":"2024-01-01T00:00:00Z"}`))
+	})
+
+	req := httptest.NewRequest("GET", "/stats", nil)
+	handler(httptest.NewRecorder(), req)
+
+	body := scrapeMetrics(t)
+	if !strings.Contains(body, `validator_validations_total{result="invalid",schema="/stats"}`) {
+		t.Errorf("expected validator_validations_total invalid series for /stats, got body:\n%s", body)
+	}
+	if !strings.Contains(body, `validator_errors_total{`) {
+		t.Errorf("expected at least one validator_errors_total series, got body:\n%s", body)
+	}
+}
+
+// TestMiddlewareUndocumentedRouteIsNotBlocked tests that a route not present
+// in the spec is passed through untouched.
+func TestMiddlewareUndocumentedRouteIsNotBlocked(t *testing.T) {
+	v := newTestValidator(t, ModeStrict)
+
+	handlerCalled := false
+	handler := v.Middleware(func(w http.ResponseWriter, r *http.Request) {
+		handlerCalled = true
+		w.WriteHeader(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/not/in/spec", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if !handlerCalled {
+		t.Error("Expected undocumented route to fall through to the handler")
+	}
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", w.Code)
+	}
+}
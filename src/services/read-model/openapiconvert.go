@@ -0,0 +1,125 @@
+package main
+
+import "strings"
+
+// specToSwagger2 renders a best-effort Swagger 2.0 form of an authored v3
+// spec (as produced by openApiHandler), covering the subset of v3 this
+// service actually uses: simple query/header parameters, JSON response
+// bodies, and component schemas. It is not a general OpenAPI v3-to-v2
+// converter.
+func specToSwagger2(v3 map[string]interface{}) map[string]interface{} {
+	swagger := map[string]interface{}{
+		"swagger":  "2.0",
+		"info":     v3["info"],
+		"produces": []string{"application/json"},
+	}
+
+	if paths, ok := v3["paths"].(map[string]interface{}); ok {
+		swagger["paths"] = convertPathsToV2(paths)
+	}
+
+	if components, ok := v3["components"].(map[string]interface{}); ok {
+		if schemas, ok := components["schemas"].(map[string]interface{}); ok {
+			swagger["definitions"] = rewriteV3Refs(schemas)
+		}
+		if params, ok := components["parameters"].(map[string]interface{}); ok {
+			swagger["parameters"] = rewriteV3Refs(params)
+		}
+	}
+
+	return swagger
+}
+
+// convertPathsToV2 walks a v3 paths object, converting each operation.
+func convertPathsToV2(paths map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(paths))
+	for path, methodsRaw := range paths {
+		methods, ok := methodsRaw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		outMethods := make(map[string]interface{}, len(methods))
+		for method, opRaw := range methods {
+			if op, ok := opRaw.(map[string]interface{}); ok {
+				outMethods[method] = convertOperationToV2(op)
+			}
+		}
+		out[path] = outMethods
+	}
+	return out
+}
+
+// convertOperationToV2 rewrites schema/parameter $refs to their v2
+// locations and flattens v3's per-media-type response content into v2's
+// single top-level response schema.
+func convertOperationToV2(op map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(op))
+	for k, v := range op {
+		if k == "responses" {
+			out[k] = convertResponsesToV2(v)
+			continue
+		}
+		out[k] = rewriteV3Refs(v)
+	}
+	return out
+}
+
+// convertResponsesToV2 pulls each response's application/json schema up to
+// a top-level "schema" field, as Swagger 2.0 expects.
+func convertResponsesToV2(v interface{}) interface{} {
+	responses, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	out := make(map[string]interface{}, len(responses))
+	for status, rRaw := range responses {
+		r, ok := rRaw.(map[string]interface{})
+		if !ok {
+			out[status] = rRaw
+			continue
+		}
+		v2r := map[string]interface{}{"description": r["description"]}
+		if content, ok := r["content"].(map[string]interface{}); ok {
+			if jsonContent, ok := content["application/json"].(map[string]interface{}); ok {
+				if schema, ok := jsonContent["schema"]; ok {
+					v2r["schema"] = rewriteV3Refs(schema)
+				}
+			}
+		}
+		out[status] = v2r
+	}
+	return out
+}
+
+// rewriteV3Refs recursively rewrites "#/components/schemas/..." and
+// "#/components/parameters/..." $refs to their Swagger 2.0 equivalents.
+func rewriteV3Refs(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, vv := range val {
+			if k == "$ref" {
+				if s, ok := vv.(string); ok {
+					out[k] = rewriteV3RefString(s)
+					continue
+				}
+			}
+			out[k] = rewriteV3Refs(vv)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, vv := range val {
+			out[i] = rewriteV3Refs(vv)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func rewriteV3RefString(ref string) string {
+	ref = strings.Replace(ref, "#/components/schemas/", "#/definitions/", 1)
+	ref = strings.Replace(ref, "#/components/parameters/", "#/parameters/", 1)
+	return ref
+}
@@ -0,0 +1,72 @@
+package main
+
+import "testing"
+
+func TestSpecToSwagger2ConvertsRefsAndResponses(t *testing.T) {
+	v3 := map[string]interface{}{
+		"info": map[string]interface{}{"title": "Test", "version": "1.0.0"},
+		"paths": map[string]interface{}{
+			"/jobs/recent": map[string]interface{}{
+				"get": map[string]interface{}{
+					"summary": "Get recent jobs",
+					"parameters": []interface{}{
+						map[string]interface{}{"$ref": "#/components/parameters/Limit"},
+					},
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{
+							"description": "Page of job objects",
+							"content": map[string]interface{}{
+								"application/json": map[string]interface{}{
+									"schema": map[string]interface{}{"$ref": "#/components/schemas/JobsPage"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		"components": map[string]interface{}{
+			"schemas": map[string]interface{}{
+				"JobsPage": map[string]interface{}{"type": "object"},
+			},
+			"parameters": map[string]interface{}{
+				"Limit": map[string]interface{}{"name": "limit", "in": "query"},
+			},
+		},
+	}
+
+	swagger := specToSwagger2(v3)
+
+	if swagger["swagger"] != "2.0" {
+		t.Errorf("expected swagger version 2.0, got %v", swagger["swagger"])
+	}
+
+	if _, ok := swagger["definitions"].(map[string]interface{})["JobsPage"]; !ok {
+		t.Error("expected JobsPage to be hoisted into definitions")
+	}
+
+	paths, ok := swagger["paths"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected paths to be present")
+	}
+	get := paths["/jobs/recent"].(map[string]interface{})["get"].(map[string]interface{})
+
+	params := get["parameters"].([]interface{})
+	ref := params[0].(map[string]interface{})["$ref"]
+	if ref != "#/parameters/Limit" {
+		t.Errorf("expected parameter $ref rewritten to #/parameters/Limit, got %v", ref)
+	}
+
+	responses := get["responses"].(map[string]interface{})
+	resp200 := responses["200"].(map[string]interface{})
+	if _, hasContent := resp200["content"]; hasContent {
+		t.Error("expected v3 content wrapper to be flattened away")
+	}
+	schema, ok := resp200["schema"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected schema to be hoisted onto the v2 response")
+	}
+	if schema["$ref"] != "#/definitions/JobsPage" {
+		t.Errorf("expected schema $ref rewritten to #/definitions/JobsPage, got %v", schema["$ref"])
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"mime"
+	"strings"
+
+	openapi_v3 "github.com/google/gnostic/openapiv3"
+	"google.golang.org/protobuf/proto"
+)
+
+// gnosticMediaPrefix/gnosticMediaSuffix match the content type the
+// Kubernetes discovery client negotiates for OpenAPI: a subtype of
+// "com.github.googleapis.gnostic.OpenAPIv3@v1.0" or "@v2", structured as
+// "+protobuf". Both name the same gnostic Document message; the version
+// identifies the wire encoding, not the OpenAPI spec version.
+const (
+	gnosticMediaPrefix = "application/com.github.googleapis.gnostic.openapiv3@"
+	gnosticMediaSuffix = "+protobuf"
+)
+
+// wantsProtobuf reports whether an Accept header asks for the gnostic
+// protobuf encoding of the OpenAPI document. On a match it returns the
+// exact media type to echo back in the response's Content-Type.
+//
+// The gnostic media type embeds an "@" version marker that is not a legal
+// RFC 2045 token character, so mime.ParseMediaType rejects it outright; we
+// still run each candidate through it for the well-formed case (params,
+// quoting) and fall back to a direct comparison only when parsing fails.
+func wantsProtobuf(accept string) (mediaType string, ok bool) {
+	for _, candidate := range strings.Split(accept, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "" {
+			continue
+		}
+		mt, _, err := mime.ParseMediaType(candidate)
+		if err != nil {
+			mt = strings.ToLower(candidate)
+		}
+		if strings.HasPrefix(mt, gnosticMediaPrefix) && strings.HasSuffix(mt, gnosticMediaSuffix) {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// marshalProtobufSpec parses the already-JSON-rendered spec into a gnostic
+// Document and serializes it with the protobuf wire format.
+func marshalProtobufSpec(specJSON []byte) ([]byte, error) {
+	doc, err := openapi_v3.ParseDocument(specJSON)
+	if err != nil {
+		return nil, fmt.Errorf("parsing spec as a gnostic document: %w", err)
+	}
+	data, err := proto.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling gnostic document: %w", err)
+	}
+	return data, nil
+}
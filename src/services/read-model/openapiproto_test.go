@@ -0,0 +1,57 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestWantsProtobufMatchesGnosticMediaType(t *testing.T) {
+	cases := []struct {
+		accept string
+		ok     bool
+	}{
+		{"application/com.github.googleapis.gnostic.OpenAPIv3@v1.0+protobuf", true},
+		{"application/com.github.googleapis.gnostic.OpenAPIv3@v2+protobuf", true},
+		{"application/json", false},
+		{"", false},
+		{"not a media type;;;", false},
+	}
+	for _, c := range cases {
+		mediaType, ok := wantsProtobuf(c.accept)
+		if ok != c.ok {
+			t.Errorf("wantsProtobuf(%q) ok = %v, want %v", c.accept, ok, c.ok)
+		}
+		if ok && mediaType == "" {
+			t.Errorf("wantsProtobuf(%q) returned ok with empty media type", c.accept)
+		}
+	}
+}
+
+func TestMarshalProtobufSpecRoundTrips(t *testing.T) {
+	spec := map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "Test",
+			"version": "1.0.0",
+		},
+		"paths": map[string]interface{}{},
+	}
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("failed to marshal fixture spec: %v", err)
+	}
+
+	data, err := marshalProtobufSpec(specJSON)
+	if err != nil {
+		t.Fatalf("marshalProtobufSpec returned error: %v", err)
+	}
+	if len(data) == 0 {
+		t.Error("expected non-empty protobuf bytes")
+	}
+}
+
+func TestMarshalProtobufSpecRejectsInvalidJSON(t *testing.T) {
+	if _, err := marshalProtobufSpec([]byte("not json")); err == nil {
+		t.Error("expected an error for invalid spec JSON")
+	}
+}
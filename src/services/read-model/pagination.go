@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/distributed-task-observatory/read-model/apierrors"
+)
+
+const (
+	defaultPageLimit = 10
+	maxPageLimit     = 100
+)
+
+// Cursor identifies the last item of a page so the next page can resume
+// strictly after it. It is opaque to callers: they only ever see the
+// base64-encoded form returned as nextCursor.
+type Cursor struct {
+	CreatedAt time.Time `json:"createdAt"`
+	ID        string    `json:"id"`
+}
+
+// encodeCursor renders c as the opaque string returned in a page's nextCursor.
+func encodeCursor(c Cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.URLEncoding.EncodeToString(data)
+}
+
+// decodeCursor parses a cursor previously returned by encodeCursor, rejecting
+// anything malformed or missing a field a resume query depends on.
+func decodeCursor(raw string) (Cursor, error) {
+	data, err := base64.URLEncoding.DecodeString(raw)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("cursor is not valid base64: %w: %w", err, apierrors.ErrInvalid)
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, fmt.Errorf("cursor is not valid: %w: %w", err, apierrors.ErrInvalid)
+	}
+	if c.ID == "" || c.CreatedAt.IsZero() {
+		return Cursor{}, fmt.Errorf("cursor is missing createdAt or id: %w", apierrors.ErrInvalid)
+	}
+	return c, nil
+}
+
+// JobsPage is the paginated envelope returned by /jobs/recent.
+type JobsPage struct {
+	Items      []Job  `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// EventsPage is the paginated envelope returned by /events.
+type EventsPage struct {
+	Items      []interface{} `json:"items"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+// parseLimit reads the `limit` query parameter, defaulting to
+// defaultPageLimit and capping at maxPageLimit.
+func parseLimit(raw string) (int, error) {
+	if raw == "" {
+		return defaultPageLimit, nil
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("limit must be a positive integer, got %q: %w", raw, apierrors.ErrInvalid)
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+	return limit, nil
+}
+
+// parseTimeParam parses an RFC3339 query parameter, returning ok=false when
+// the parameter wasn't supplied at all.
+func parseTimeParam(raw string) (t time.Time, ok bool, err error) {
+	if raw == "" {
+		return time.Time{}, false, nil
+	}
+	t, err = time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("must be RFC3339, got %q: %w", raw, apierrors.ErrInvalid)
+	}
+	return t, true, nil
+}
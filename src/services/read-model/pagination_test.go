@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseLimitDefault tests parseLimit returns the default when unset.
+func TestParseLimitDefault(t *testing.T) {
+	limit, err := parseLimit("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if limit != defaultPageLimit {
+		t.Errorf("Expected %d, got %d", defaultPageLimit, limit)
+	}
+}
+
+// TestParseLimitCapsAtMax tests parseLimit caps values above maxPageLimit.
+func TestParseLimitCapsAtMax(t *testing.T) {
+	limit, err := parseLimit("1000")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if limit != maxPageLimit {
+		t.Errorf("Expected %d, got %d", maxPageLimit, limit)
+	}
+}
+
+// TestParseLimitRejectsNonPositive tests parseLimit rejects zero and negative values.
+func TestParseLimitRejectsNonPositive(t *testing.T) {
+	for _, raw := range []string{"0", "-1", "not-a-number"} {
+		if _, err := parseLimit(raw); err == nil {
+			t.Errorf("Expected error for limit=%q, got none", raw)
+		}
+	}
+}
+
+// TestEncodeDecodeCursorRoundTrip tests that decoding an encoded cursor
+// returns the original value.
+func TestEncodeDecodeCursorRoundTrip(t *testing.T) {
+	want := Cursor{CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), ID: "job-42"}
+	raw := encodeCursor(want)
+
+	got, err := decodeCursor(raw)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !got.CreatedAt.Equal(want.CreatedAt) || got.ID != want.ID {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
+
+// TestDecodeCursorRejectsMalformed tests decodeCursor rejects invalid input.
+func TestDecodeCursorRejectsMalformed(t *testing.T) {
+	for _, raw := range []string{"not-base64!!", "", "e30="} {
+		if _, err := decodeCursor(raw); err == nil {
+			t.Errorf("Expected error decoding %q, got none", raw)
+		}
+	}
+}
+
+// TestParseTimeParamNotProvided tests parseTimeParam reports ok=false when
+// the parameter is absent.
+func TestParseTimeParamNotProvided(t *testing.T) {
+	_, ok, err := parseTimeParam("")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if ok {
+		t.Error("Expected ok=false for an empty parameter")
+	}
+}
+
+// TestParseTimeParamRejectsInvalidFormat tests parseTimeParam rejects
+// timestamps that aren't RFC3339.
+func TestParseTimeParamRejectsInvalidFormat(t *testing.T) {
+	if _, _, err := parseTimeParam("2026-01-02"); err == nil {
+		t.Error("Expected error for non-RFC3339 timestamp, got none")
+	}
+}
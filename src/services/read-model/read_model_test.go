@@ -9,8 +9,23 @@ import (
 	"regexp"
 	"strings"
 	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/distributed-task-observatory/read-model/openapi"
 )
 
+// TestMain loads the OpenAPI spec once so handlers under test (openApiHandler,
+// and anything wrapped in openapiValidator.Middleware) have a non-nil validator.
+func TestMain(m *testing.M) {
+	v, err := openapi.NewValidator(openapi.ModeReport)
+	if err != nil {
+		panic(err)
+	}
+	openapiValidator = v
+	os.Exit(m.Run())
+}
+
 // ReadVersion reads the VERSION file and returns the version string.
 func ReadVersion() (string, error) {
 	dir, err := os.Getwd()
@@ -380,16 +395,17 @@ func TestCorsMiddlewarePassesThrough(t *testing.T) {
 // Tests for health handler
 // ============================================================
 
-// TestHealthHandler tests the health endpoint.
+// TestHealthHandler tests the health endpoint, and that tracingMiddleware
+// emits exactly one OK span carrying the expected HTTP attributes.
 func TestHealthHandler(t *testing.T) {
 	ServiceVersion = "1.2.3"
 
 	req := httptest.NewRequest("GET", "/health", nil)
-	w := httptest.NewRecorder()
+	tr := newTracingRecorder(t)
 
-	healthHandler(w, req)
+	tracingMiddleware("/health", healthHandler)(tr, req)
 
-	resp := w.Result()
+	resp := tr.Result()
 	if resp.StatusCode != http.StatusOK {
 		t.Errorf("Expected status 200, got %d", resp.StatusCode)
 	}
@@ -399,6 +415,10 @@ func TestHealthHandler(t *testing.T) {
 		t.Errorf("Expected Content-Type 'application/json', got '%s'", contentType)
 	}
 
+	if resp.Header.Get("X-Trace-Id") == "" {
+		t.Error("Expected X-Trace-Id header to be set")
+	}
+
 	var health HealthResponse
 	if err := json.NewDecoder(resp.Body).Decode(&health); err != nil {
 		t.Fatalf("Failed to decode health response: %v", err)
@@ -410,6 +430,32 @@ func TestHealthHandler(t *testing.T) {
 	if health.Version != "1.2.3" {
 		t.Errorf("Expected version '1.2.3', got '%s'", health.Version)
 	}
+
+	spans := tr.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("Expected exactly one span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Status().Code != codes.Ok {
+		t.Errorf("Expected span status Ok, got %v", span.Status().Code)
+	}
+
+	attrs := map[string]string{}
+	for _, kv := range span.Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["http.method"] != "GET" {
+		t.Errorf("Expected http.method GET, got %q", attrs["http.method"])
+	}
+	if attrs["http.route"] != "/health" {
+		t.Errorf("Expected http.route /health, got %q", attrs["http.route"])
+	}
+	if attrs["service.version"] != "1.2.3" {
+		t.Errorf("Expected service.version 1.2.3, got %q", attrs["service.version"])
+	}
+	if attrs["http.status_code"] != "200" {
+		t.Errorf("Expected http.status_code 200, got %q", attrs["http.status_code"])
+	}
 }
 
 // ============================================================
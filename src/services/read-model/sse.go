@@ -0,0 +1,331 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// heartbeatInterval is how often idle SSE connections receive a keep-alive comment.
+const heartbeatInterval = 15 * time.Second
+
+// StreamEvent is a single item pushed to SSE subscribers.
+type StreamEvent struct {
+	ID    string
+	Type  string
+	JobID string
+	Data  []byte
+}
+
+// EventFilter narrows a subscription to a jobId, an event type, or both. The
+// zero value matches every event.
+type EventFilter struct {
+	JobID string
+	Type  string
+}
+
+// matches reports whether ev satisfies f.
+func (f EventFilter) matches(ev StreamEvent) bool {
+	if f.JobID != "" && f.JobID != ev.JobID {
+		return false
+	}
+	if f.Type != "" && f.Type != ev.Type {
+		return false
+	}
+	return true
+}
+
+// eventSubscriber is a single subscriber's channel plus the filter it registered with.
+type eventSubscriber struct {
+	ch     chan StreamEvent
+	filter EventFilter
+}
+
+// EventHub fans out StreamEvents to subscribed SSE connections and retains
+// the most recent ones in an in-memory ring buffer so a reconnecting client
+// can replay recent history without a Mongo round trip.
+type EventHub struct {
+	mu   sync.RWMutex
+	subs map[chan StreamEvent]eventSubscriber
+	buf  *eventRingBuffer
+}
+
+// NewEventHub creates an empty hub ready to accept subscribers, retaining up
+// to bufferSize recent events for replay.
+func NewEventHub(bufferSize int) *EventHub {
+	return &EventHub{
+		subs: make(map[chan StreamEvent]eventSubscriber),
+		buf:  newEventRingBuffer(bufferSize),
+	}
+}
+
+// Subscribe registers a new subscriber matching filter (the zero value
+// receives everything). The returned unsubscribe func must be called when
+// the caller is done consuming the channel.
+func (h *EventHub) Subscribe(filter EventFilter) (<-chan StreamEvent, func()) {
+	ch := make(chan StreamEvent, 16)
+
+	h.mu.Lock()
+	h.subs[ch] = eventSubscriber{ch: ch, filter: filter}
+	h.mu.Unlock()
+
+	unsubscribe := func() {
+		h.mu.Lock()
+		if _, ok := h.subs[ch]; ok {
+			delete(h.subs, ch)
+			close(ch)
+		}
+		h.mu.Unlock()
+	}
+	return ch, unsubscribe
+}
+
+// Broadcast records ev in the replay buffer and delivers it to every
+// subscriber whose filter matches. Slow subscribers are dropped from the
+// fan-out for this event rather than blocking the publisher.
+func (h *EventHub) Broadcast(ev StreamEvent) {
+	h.buf.append(ev)
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, sub := range h.subs {
+		if !sub.filter.matches(ev) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			log.Printf("sse: dropping event %s for slow subscriber", ev.ID)
+		}
+	}
+}
+
+// replaySince returns the buffered events recorded after lastEventID, in
+// order, filtered by filter. ok is false when lastEventID isn't present in
+// the buffer (too old, or the buffer has wrapped), meaning the caller
+// should fall back to an out-of-band replay.
+func (h *EventHub) replaySince(lastEventID string, filter EventFilter) (events []StreamEvent, ok bool) {
+	all, ok := h.buf.since(lastEventID)
+	if !ok {
+		return nil, false
+	}
+	for _, ev := range all {
+		if filter.matches(ev) {
+			events = append(events, ev)
+		}
+	}
+	return events, true
+}
+
+// eventRingBuffer is a fixed-capacity, in-memory replay log of recent
+// StreamEvents; the oldest entries are dropped once it's full.
+type eventRingBuffer struct {
+	mu    sync.Mutex
+	buf   []StreamEvent
+	limit int
+}
+
+func newEventRingBuffer(limit int) *eventRingBuffer {
+	if limit <= 0 {
+		limit = 1
+	}
+	return &eventRingBuffer{limit: limit}
+}
+
+func (b *eventRingBuffer) append(ev StreamEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, ev)
+	if len(b.buf) > b.limit {
+		b.buf = b.buf[len(b.buf)-b.limit:]
+	}
+}
+
+// since returns the events recorded strictly after lastEventID, in order,
+// and whether lastEventID was found in the buffer at all.
+func (b *eventRingBuffer) since(lastEventID string) ([]StreamEvent, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for i, ev := range b.buf {
+		if ev.ID == lastEventID {
+			out := make([]StreamEvent, len(b.buf)-i-1)
+			copy(out, b.buf[i+1:])
+			return out, true
+		}
+	}
+	return nil, false
+}
+
+// eventsStreamHandler upgrades the connection to text/event-stream and
+// relays events published to hub. When the client supplies a Last-Event-ID
+// header, missed events are replayed from hub's ring buffer, or from
+// eventsColl if that ID is no longer buffered.
+func eventsStreamHandler(hub *EventHub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		filter := EventFilter{
+			JobID: r.URL.Query().Get("jobId"),
+			Type:  r.URL.Query().Get("type"),
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		if lastEventID := r.Header.Get("Last-Event-ID"); lastEventID != "" {
+			if events, ok := hub.replaySince(lastEventID, filter); ok {
+				for _, ev := range events {
+					writeSSEEvent(w, ev)
+				}
+				flusher.Flush()
+			} else {
+				replayEvents(r.Context(), w, flusher, lastEventID, filter)
+			}
+		}
+
+		sub, unsubscribe := hub.Subscribe(filter)
+		defer unsubscribe()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+				flusher.Flush()
+			case ev, ok := <-sub:
+				if !ok {
+					return
+				}
+				writeSSEEvent(w, ev)
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+// replayEvents streams events recorded after lastEventID from eventsColl so a
+// reconnecting client can catch up before joining the live fan-out.
+func replayEvents(ctx context.Context, w http.ResponseWriter, flusher http.Flusher, lastEventID string, filter EventFilter) {
+	if eventsColl == nil {
+		return
+	}
+
+	after, err := lastOccurredAt(ctx, lastEventID)
+	if err != nil {
+		log.Printf("sse: could not resolve Last-Event-ID %q: %v", lastEventID, err)
+		return
+	}
+
+	mongoFilter := bson.M{"occurredAt": bson.M{"$gt": after}}
+	if filter.JobID != "" {
+		mongoFilter["payload.id"] = filter.JobID
+	}
+	if filter.Type != "" {
+		mongoFilter["eventType"] = filter.Type
+	}
+
+	cursor, err := eventsColl.Find(ctx, mongoFilter, options.Find().SetSort(bson.M{"occurredAt": 1}))
+	if err != nil {
+		log.Printf("sse: replay query failed: %v", err)
+		return
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			continue
+		}
+		ev, err := streamEventFromDoc(doc)
+		if err != nil {
+			continue
+		}
+		writeSSEEvent(w, ev)
+	}
+	flusher.Flush()
+}
+
+// lastOccurredAt looks up the occurredAt timestamp of the event identified by
+// eventID so replay can resume strictly after it.
+func lastOccurredAt(ctx context.Context, eventID string) (time.Time, error) {
+	var doc bson.M
+	err := eventsColl.FindOne(ctx, bson.M{"eventId": eventID}).Decode(&doc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	occurredAt, _ := doc["occurredAt"].(time.Time)
+	return occurredAt, nil
+}
+
+// streamEventFromDoc converts a raw Mongo event document into a StreamEvent.
+func streamEventFromDoc(doc bson.M) (StreamEvent, error) {
+	data, err := bson.MarshalExtJSON(doc, false, false)
+	if err != nil {
+		return StreamEvent{}, err
+	}
+	eventID, _ := doc["eventId"].(string)
+	eventType, _ := doc["eventType"].(string)
+	jobID := ""
+	if payload, ok := doc["payload"].(bson.M); ok {
+		jobID, _ = payload["id"].(string)
+	}
+	return StreamEvent{ID: eventID, Type: eventType, JobID: jobID, Data: data}, nil
+}
+
+// writeSSEEvent frames a StreamEvent as an id:/event:/data: block.
+func writeSSEEvent(w http.ResponseWriter, ev StreamEvent) {
+	if ev.ID != "" {
+		fmt.Fprintf(w, "id: %s\n", ev.ID)
+	}
+	if ev.Type != "" {
+		fmt.Fprintf(w, "event: %s\n", ev.Type)
+	}
+	fmt.Fprintf(w, "data: %s\n\n", ev.Data)
+}
+
+// watchEventChangeStream tails eventsColl via a Mongo change stream and
+// broadcasts every inserted document to hub until ctx is cancelled.
+func watchEventChangeStream(ctx context.Context, coll *mongo.Collection, hub *EventHub) {
+	pipeline := mongo.Pipeline{
+		bson.D{{Key: "$match", Value: bson.D{{Key: "operationType", Value: "insert"}}}},
+	}
+	stream, err := coll.Watch(ctx, pipeline)
+	if err != nil {
+		log.Printf("sse: failed to open change stream: %v", err)
+		return
+	}
+	defer stream.Close(ctx)
+
+	for stream.Next(ctx) {
+		var change struct {
+			FullDocument bson.M `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&change); err != nil {
+			continue
+		}
+		ev, err := streamEventFromDoc(change.FullDocument)
+		if err != nil {
+			continue
+		}
+		hub.Broadcast(ev)
+	}
+}
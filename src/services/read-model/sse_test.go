@@ -0,0 +1,294 @@
+package main
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestEventHubBroadcastToAllSubscribers tests that unfiltered subscribers
+// receive every published event.
+func TestEventHubBroadcastToAllSubscribers(t *testing.T) {
+	hub := NewEventHub(16)
+	ch, unsubscribe := hub.Subscribe(EventFilter{})
+	defer unsubscribe()
+
+	hub.Broadcast(StreamEvent{ID: "1", Type: "job.completed", JobID: "job-a", Data: []byte(`{}`)})
+
+	select {
+	case ev := <-ch:
+		if ev.ID != "1" {
+			t.Errorf("Expected event ID '1', got '%s'", ev.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for broadcast event")
+	}
+}
+
+// TestEventHubFiltersByJobID tests that a filtered subscriber only receives
+// events for its jobId.
+func TestEventHubFiltersByJobID(t *testing.T) {
+	hub := NewEventHub(16)
+	ch, unsubscribe := hub.Subscribe(EventFilter{JobID: "job-a"})
+	defer unsubscribe()
+
+	hub.Broadcast(StreamEvent{ID: "1", JobID: "job-b", Data: []byte(`{}`)})
+	hub.Broadcast(StreamEvent{ID: "2", JobID: "job-a", Data: []byte(`{}`)})
+
+	select {
+	case ev := <-ch:
+		if ev.ID != "2" {
+			t.Errorf("Expected only matching event '2', got '%s'", ev.ID)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for filtered broadcast event")
+	}
+
+	select {
+	case ev := <-ch:
+		t.Errorf("Expected no further events, got %+v", ev)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+// TestEventHubUnsubscribeStopsDelivery tests that events stop arriving after
+// unsubscribe.
+func TestEventHubUnsubscribeStopsDelivery(t *testing.T) {
+	hub := NewEventHub(16)
+	ch, unsubscribe := hub.Subscribe(EventFilter{})
+	unsubscribe()
+
+	hub.Broadcast(StreamEvent{ID: "1", Data: []byte(`{}`)})
+
+	if _, ok := <-ch; ok {
+		t.Error("Expected channel to be closed after unsubscribe")
+	}
+}
+
+// TestEventsStreamHandlerSetsContentType tests the SSE content type and
+// framing of a published event.
+func TestEventsStreamHandlerSetsContentType(t *testing.T) {
+	hub := NewEventHub(16)
+	server := httptest.NewServer(eventsStreamHandler(hub))
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("Expected Content-Type 'text/event-stream', got '%s'", ct)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		hub.Broadcast(StreamEvent{ID: "evt-1", Type: "job.completed", JobID: "job-1", Data: []byte(`{"id":"job-1"}`)})
+	}()
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for i := 0; i < 3; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed reading SSE stream: %v", err)
+		}
+		lines = append(lines, strings.TrimRight(line, "\n"))
+	}
+
+	joined := strings.Join(lines, "\n")
+	if !strings.Contains(joined, "id: evt-1") {
+		t.Errorf("Expected 'id: evt-1' frame, got: %s", joined)
+	}
+	if !strings.Contains(joined, "event: job.completed") {
+		t.Errorf("Expected 'event: job.completed' frame, got: %s", joined)
+	}
+	if !strings.Contains(joined, `data: {"id":"job-1"}`) {
+		t.Errorf("Expected data frame with payload, got: %s", joined)
+	}
+}
+
+// TestEventsStreamHandlerFiltersByType tests that ?type= excludes
+// non-matching events from the stream.
+func TestEventsStreamHandlerFiltersByType(t *testing.T) {
+	hub := NewEventHub(16)
+	server := httptest.NewServer(eventsStreamHandler(hub))
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"?type=job.completed", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer resp.Body.Close()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		hub.Broadcast(StreamEvent{ID: "1", Type: "job.failed", Data: []byte(`{}`)})
+		hub.Broadcast(StreamEvent{ID: "2", Type: "job.completed", Data: []byte(`{}`)})
+	}()
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for i := 0; i < 3; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed reading SSE stream: %v", err)
+		}
+		lines = append(lines, strings.TrimRight(line, "\n"))
+	}
+
+	joined := strings.Join(lines, "\n")
+	if strings.Contains(joined, "id: 1\n") {
+		t.Errorf("Expected filtered subscriber to skip non-matching event, got: %s", joined)
+	}
+	if !strings.Contains(joined, "id: 2") {
+		t.Errorf("Expected filtered subscriber to receive matching event, got: %s", joined)
+	}
+}
+
+// TestEventsStreamHandlerReplaysFromRingBuffer tests that a client
+// reconnecting with Last-Event-ID receives the events broadcast after it,
+// in order with monotonically increasing IDs, before joining the live feed.
+func TestEventsStreamHandlerReplaysFromRingBuffer(t *testing.T) {
+	hub := NewEventHub(16)
+	hub.Broadcast(StreamEvent{ID: "1", Type: "job.completed", Data: []byte(`{"seq":1}`)})
+	hub.Broadcast(StreamEvent{ID: "2", Type: "job.completed", Data: []byte(`{"seq":2}`)})
+	hub.Broadcast(StreamEvent{ID: "3", Type: "job.completed", Data: []byte(`{"seq":3}`)})
+
+	server := httptest.NewServer(eventsStreamHandler(hub))
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	var ids []string
+	for len(ids) < 2 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed reading SSE stream: %v", err)
+		}
+		if id, ok := strings.CutPrefix(line, "id: "); ok {
+			ids = append(ids, strings.TrimSpace(id))
+		}
+	}
+
+	if ids[0] != "2" || ids[1] != "3" {
+		t.Errorf("Expected replayed events [2 3] in order, got %v", ids)
+	}
+}
+
+// TestEventRingBufferDropsOldestOnceFull tests that since() reports !ok for
+// an event ID that has fallen off the front of a full buffer.
+func TestEventRingBufferDropsOldestOnceFull(t *testing.T) {
+	buf := newEventRingBuffer(2)
+	buf.append(StreamEvent{ID: "1"})
+	buf.append(StreamEvent{ID: "2"})
+	buf.append(StreamEvent{ID: "3"})
+
+	if _, ok := buf.since("1"); ok {
+		t.Error("Expected event '1' to have been evicted from a 2-capacity buffer")
+	}
+
+	events, ok := buf.since("2")
+	if !ok {
+		t.Fatal("Expected event '2' to still be present")
+	}
+	if len(events) != 1 || events[0].ID != "3" {
+		t.Errorf("Expected [3] after '2', got %v", events)
+	}
+}
+
+// TestEventsStreamHandlerFiltersByJobID tests that ?jobId= excludes
+// non-matching events from the stream.
+func TestEventsStreamHandlerFiltersByJobID(t *testing.T) {
+	hub := NewEventHub(16)
+	server := httptest.NewServer(eventsStreamHandler(hub))
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"?jobId=job-a", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer resp.Body.Close()
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		hub.Broadcast(StreamEvent{ID: "1", JobID: "job-b", Data: []byte(`{}`)})
+		hub.Broadcast(StreamEvent{ID: "2", JobID: "job-a", Data: []byte(`{}`)})
+	}()
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for i := 0; i < 3; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed reading SSE stream: %v", err)
+		}
+		lines = append(lines, strings.TrimRight(line, "\n"))
+	}
+
+	joined := strings.Join(lines, "\n")
+	if strings.Contains(joined, "id: 1\n") {
+		t.Errorf("Expected filtered subscriber to skip non-matching event, got: %s", joined)
+	}
+	if !strings.Contains(joined, "id: 2") {
+		t.Errorf("Expected filtered subscriber to receive matching event, got: %s", joined)
+	}
+}
+
+// TestEventsStreamHandlerWorksThroughMiddlewareChain tests that
+// eventsStreamHandler still streams once wrapped the way main.go actually
+// wires it, through instrumentMiddleware and tracingMiddleware. Those
+// middlewares swap in a statusRecorder ResponseWriter, which must still
+// satisfy http.Flusher or the handler's w.(http.Flusher) assertion fails
+// and every real request gets a 500 instead of a stream.
+func TestEventsStreamHandlerWorksThroughMiddlewareChain(t *testing.T) {
+	hub := NewEventHub(16)
+	wrapped := tracingMiddleware("/events/stream", instrumentMiddleware("/events/stream", eventsStreamHandler(hub)))
+	server := httptest.NewServer(wrapped)
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL, nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d (streaming unsupported through middleware?)", resp.StatusCode)
+	}
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		hub.Broadcast(StreamEvent{ID: "evt-1", Type: "job.completed", JobID: "job-1", Data: []byte(`{"id":"job-1"}`)})
+	}()
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for i := 0; i < 3; i++ {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed reading SSE stream through middleware chain: %v", err)
+		}
+		lines = append(lines, strings.TrimRight(line, "\n"))
+	}
+
+	joined := strings.Join(lines, "\n")
+	if !strings.Contains(joined, "id: evt-1") {
+		t.Errorf("Expected 'id: evt-1' frame through middleware chain, got: %s", joined)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "read-model"
+
+var tracer = otel.Tracer(tracerName)
+
+// recentSpans retains the most recently completed spans for /debug/traces;
+// it's installed as a SpanProcessor alongside the OTLP batcher so local
+// inspection doesn't depend on a collector being reachable.
+var recentSpans = newRecentSpanBuffer(50)
+
+// newTracerProvider builds the service's TracerProvider, exporting spans
+// over OTLP/HTTP to endpoint and mirroring the most recent ones into
+// recentSpans for local inspection.
+func newTracerProvider(ctx context.Context, endpoint string) (*sdktrace.TracerProvider, error) {
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(endpoint), otlptracehttp.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP trace exporter: %w", err)
+	}
+
+	res, err := resource.New(ctx, resource.WithAttributes(
+		semconv.ServiceName("read-model"),
+		semconv.ServiceVersion(ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("building trace resource: %w", err)
+	}
+
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSpanProcessor(recentSpans),
+		sdktrace.WithResource(res),
+	), nil
+}
+
+// tracingMiddleware starts a span per request under tracer, extracting any
+// incoming W3C traceparent header so a caller's trace continues rather than
+// restarting, and writes the resulting trace ID back as X-Trace-Id so
+// clients and logs can correlate without an OTel SDK of their own.
+func tracingMiddleware(route string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		ctx, span := tracer.Start(ctx, route, trace.WithAttributes(
+			attribute.String("http.method", r.Method),
+			attribute.String("http.route", route),
+			attribute.String("service.version", ServiceVersion),
+		))
+		defer span.End()
+
+		w.Header().Set("X-Trace-Id", span.SpanContext().TraceID().String())
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(rec, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.status_code", rec.status))
+		if rec.status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		} else {
+			span.SetStatus(codes.Ok, "")
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// tracingRecorder bundles an httptest.ResponseRecorder with an in-memory
+// span recorder installed as the active TracerProvider, so a test can run
+// a handler through tracingMiddleware and then assert on what it emitted.
+type tracingRecorder struct {
+	*httptest.ResponseRecorder
+	spans *tracetest.SpanRecorder
+}
+
+// newTracingRecorder installs an in-memory TracerProvider for the duration
+// of t, restoring the previous global one on cleanup.
+func newTracingRecorder(t *testing.T) *tracingRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder),
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+	)
+
+	prevProvider := otel.GetTracerProvider()
+	prevTracer := tracer
+	otel.SetTracerProvider(tp)
+	tracer = tp.Tracer(tracerName)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		tracer = prevTracer
+	})
+
+	return &tracingRecorder{ResponseRecorder: httptest.NewRecorder(), spans: recorder}
+}
+
+// Ended returns the spans that completed since newTracingRecorder was called.
+func (r *tracingRecorder) Ended() []sdktrace.ReadOnlySpan {
+	return r.spans.Ended()
+}
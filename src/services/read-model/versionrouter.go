@@ -0,0 +1,105 @@
+package main
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// acceptVersionPattern extracts the version from an
+// Accept: application/vnd.oddessentials.v2+json media type.
+var acceptVersionPattern = regexp.MustCompile(`application/vnd\.oddessentials\.(v[\d.]+)\+json`)
+
+// versionedHandler is one version-gated implementation of a logical route.
+// hasMax distinguishes "valid through max" from "valid from min onward with
+// no upper bound" (the zero value of APIVersion is itself a valid max, so a
+// bool is needed rather than a sentinel).
+type versionedHandler struct {
+	min     APIVersion
+	max     APIVersion
+	hasMax  bool
+	sunset  time.Time
+	handler http.HandlerFunc
+}
+
+// VersionRouter dispatches each logical route to the handler registered for
+// whichever API version the client requested, resolved from a /v{N}/ URL
+// prefix or an Accept: application/vnd.oddessentials.v{N}+json media type,
+// defaulting to DefaultAPIVersion when neither is present. Handlers
+// registered with RegisterDeprecated get automatic Deprecation/Sunset
+// response headers whenever they're the one serving the request.
+type VersionRouter struct {
+	routes map[string][]versionedHandler
+}
+
+// NewVersionRouter returns an empty router ready for Register calls.
+func NewVersionRouter() *VersionRouter {
+	return &VersionRouter{routes: make(map[string][]versionedHandler)}
+}
+
+// Register adds handler as the implementation of path for versions >= min,
+// with no upper bound (it also serves any future version until a narrower
+// registration supersedes it).
+func (vr *VersionRouter) Register(path string, min APIVersion, handler http.HandlerFunc) {
+	vr.routes[path] = append(vr.routes[path], versionedHandler{min: min, handler: handler})
+}
+
+// RegisterDeprecated adds handler as the implementation of path for the
+// closed range [min, max]. Requests served by it receive a Deprecation
+// header and a Sunset header set to sunset.
+func (vr *VersionRouter) RegisterDeprecated(path string, min, max APIVersion, sunset time.Time, handler http.HandlerFunc) {
+	vr.routes[path] = append(vr.routes[path], versionedHandler{min: min, max: max, hasMax: true, sunset: sunset, handler: handler})
+}
+
+// requestedVersion resolves the API version a request named, preferring a
+// /v{N}/ URL prefix (returning the remaining path with that prefix
+// stripped) and falling back to the Accept header's vnd media type, then
+// DefaultAPIVersion if neither was supplied.
+func requestedVersion(r *http.Request) (version APIVersion, path string) {
+	if rest, ok := strings.CutPrefix(r.URL.Path, "/v"); ok {
+		if slash := strings.IndexByte(rest, '/'); slash >= 0 {
+			if v, err := ParseAPIVersion(rest[:slash]); err == nil {
+				return v, rest[slash:]
+			}
+		}
+	}
+	if m := acceptVersionPattern.FindStringSubmatch(r.Header.Get("Accept")); m != nil {
+		if v, err := ParseAPIVersion(m[1]); err == nil {
+			return v, r.URL.Path
+		}
+	}
+	return DefaultAPIVersion, r.URL.Path
+}
+
+// Handler returns the http.HandlerFunc to register with the ServeMux for
+// path, dispatching each request to whichever registered version matches.
+func (vr *VersionRouter) Handler(path string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		version, _ := requestedVersion(r)
+
+		var best *versionedHandler
+		for i := range vr.routes[path] {
+			h := &vr.routes[path][i]
+			if version.LessThan(h.min) {
+				continue
+			}
+			if h.hasMax && version.GreaterThanOrEqualTo(APIVersion{Major: h.max.Major, Minor: h.max.Minor + 1}) {
+				continue
+			}
+			if best == nil || best.min.LessThan(h.min) {
+				best = h
+			}
+		}
+		if best == nil {
+			http.Error(w, "unsupported API version "+version.String(), http.StatusNotFound)
+			return
+		}
+
+		if best.hasMax {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Sunset", best.sunset.UTC().Format(http.TimeFormat))
+		}
+		best.handler(w, r)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestAPIVersionComparisons tests LessThan and GreaterThanOrEqualTo across
+// major and minor components.
+func TestAPIVersionComparisons(t *testing.T) {
+	if !(APIVersion{Major: 1}).LessThan(APIVersion{Major: 2}) {
+		t.Error("Expected v1 < v2")
+	}
+	if (APIVersion{Major: 2}).LessThan(APIVersion{Major: 1}) {
+		t.Error("Expected v2 not < v1")
+	}
+	if !(APIVersion{Major: 1, Minor: 1}).GreaterThanOrEqualTo(APIVersion{Major: 1}) {
+		t.Error("Expected v1.1 >= v1")
+	}
+	if (APIVersion{Major: 1}).GreaterThanOrEqualTo(APIVersion{Major: 1, Minor: 1}) {
+		t.Error("Expected v1 not >= v1.1")
+	}
+}
+
+// TestParseAPIVersion tests parsing of the accepted version formats.
+func TestParseAPIVersion(t *testing.T) {
+	cases := map[string]APIVersion{
+		"v1":  {Major: 1},
+		"v2":  {Major: 2},
+		"1":   {Major: 1},
+		"1.2": {Major: 1, Minor: 2},
+	}
+	for raw, want := range cases {
+		got, err := ParseAPIVersion(raw)
+		if err != nil {
+			t.Errorf("ParseAPIVersion(%q) returned error: %v", raw, err)
+		}
+		if got != want {
+			t.Errorf("ParseAPIVersion(%q) = %+v, want %+v", raw, got, want)
+		}
+	}
+}
+
+// TestParseAPIVersionRejectsGarbage tests that non-numeric input errors.
+func TestParseAPIVersionRejectsGarbage(t *testing.T) {
+	if _, err := ParseAPIVersion("latest"); err == nil {
+		t.Error("Expected error for non-numeric version, got none")
+	}
+}
+
+func newTestVersionRouter() *VersionRouter {
+	vr := NewVersionRouter()
+	sunset := time.Date(2027, time.January, 31, 0, 0, 0, 0, time.UTC)
+	vr.RegisterDeprecated("/health", APIVersionV1, APIVersionV1, sunset, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("v1"))
+	})
+	vr.Register("/health", APIVersionV2, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("v2"))
+	})
+	return vr
+}
+
+// TestVersionRouterDefaultsToDeprecatedV1 tests that a request naming no
+// version at all is routed to v1 and marked deprecated.
+func TestVersionRouterDefaultsToDeprecatedV1(t *testing.T) {
+	handler := newTestVersionRouter().Handler("/health")
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if body := w.Body.String(); body != "v1" {
+		t.Errorf("Expected v1 body, got %q", body)
+	}
+	if w.Header().Get("Deprecation") != "true" {
+		t.Errorf("Expected Deprecation: true, got %q", w.Header().Get("Deprecation"))
+	}
+	if w.Header().Get("Sunset") == "" {
+		t.Error("Expected a Sunset header")
+	}
+}
+
+// TestVersionRouterURLPrefixSelectsVersion tests that a /v2/ URL prefix
+// routes to the v2 handler without deprecation headers.
+func TestVersionRouterURLPrefixSelectsVersion(t *testing.T) {
+	handler := newTestVersionRouter().Handler("/health")
+
+	req := httptest.NewRequest("GET", "/v2/health", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if body := w.Body.String(); body != "v2" {
+		t.Errorf("Expected v2 body, got %q", body)
+	}
+	if w.Header().Get("Deprecation") != "" {
+		t.Errorf("Expected no Deprecation header, got %q", w.Header().Get("Deprecation"))
+	}
+}
+
+// TestVersionRouterAcceptHeaderSelectsVersion tests that an Accept vnd media
+// type routes to the matching version.
+func TestVersionRouterAcceptHeaderSelectsVersion(t *testing.T) {
+	handler := newTestVersionRouter().Handler("/health")
+
+	req := httptest.NewRequest("GET", "/health", nil)
+	req.Header.Set("Accept", "application/vnd.oddessentials.v2+json")
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if body := w.Body.String(); body != "v2" {
+		t.Errorf("Expected v2 body, got %q", body)
+	}
+}
+
+// TestVersionRouterUnsupportedVersionIs404 tests that naming a version older
+// than every registered handler returns 404.
+func TestVersionRouterUnsupportedVersionIs404(t *testing.T) {
+	handler := newTestVersionRouter().Handler("/health")
+
+	req := httptest.NewRequest("GET", "/v0/health", nil)
+	w := httptest.NewRecorder()
+	handler(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404, got %d", w.Code)
+	}
+}